@@ -0,0 +1,71 @@
+// cmd/admin/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/repositories/postgres"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/repositories/redis"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/application"
+)
+
+// Bootstraps the first platform administrator:
+//
+//	ADMIN_PASSWORD=... ./admin -email admin@example.com -username admin
+func main() {
+	configPath := flag.String("config", "./config", "Path to configuration directory")
+	email := flag.String("email", "", "Email of the admin account")
+	username := flag.String("username", "", "Username of the admin account")
+	flag.Parse()
+
+	// Read from the environment so the password does not end up in the shell history
+	password := os.Getenv("ADMIN_PASSWORD")
+
+	if *email == "" || *username == "" || password == "" {
+		flag.Usage()
+		log.Fatal("-email, -username and the ADMIN_PASSWORD environment variable are required")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	dbConn, err := postgres.NewConnection(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer postgres.Close(dbConn)
+
+	redisConn, err := redis.NewConnection(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisConn.Close()
+
+	txManager := postgres.NewTransactionManager(dbConn)
+
+	userRepo := postgres.NewUserRepository(dbConn)
+	restaurantRepo := postgres.NewRestaurantRepository(dbConn)
+	volunteerRepo := postgres.NewVolunteerRepository(dbConn)
+	eventRepo := postgres.NewEventRepository(dbConn)
+	branchRepo := postgres.NewBranchRepository(dbConn)
+	volunteerAppRepo := postgres.NewVolunteerApplicationRepository(dbConn)
+	eventVolunteerRepo := postgres.NewEventVolunteerRepository(dbConn)
+	securityPolicyRepo := postgres.NewSecurityPolicyRepository(dbConn)
+	auditLogRepo := postgres.NewAuditLogRepository(dbConn)
+	tokenCache := redis.NewTokenCache(redisConn)
+
+	adminService := application.NewAdminService(txManager, userRepo, restaurantRepo, volunteerRepo, eventRepo, branchRepo, volunteerAppRepo, eventVolunteerRepo, securityPolicyRepo, auditLogRepo, tokenCache)
+
+	user, err := adminService.CreateAdmin(context.Background(), *email, *username, password)
+	if err != nil {
+		log.Fatalf("Failed to create admin: %v", err)
+	}
+
+	log.Printf("Admin %s (%s) created", user.Username, user.ID)
+}
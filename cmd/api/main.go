@@ -9,11 +9,15 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/mail"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/oidc"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/repositories/postgres"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/repositories/redis"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/storage/local"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/application"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/jwt"
 )
@@ -44,32 +48,100 @@ func main() {
 	}
 	defer redisConn.Close()
 
+	fileStorage, err := local.NewFileStorage(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize file storage: %v", err)
+	}
+
 	txManager := postgres.NewTransactionManager(dbConn)
 
 	userRepo := postgres.NewUserRepository(dbConn)
 	restaurantRepo := postgres.NewRestaurantRepository(dbConn)
+	branchRepo := postgres.NewBranchRepository(dbConn)
 	volunteerRepo := postgres.NewVolunteerRepository(dbConn)
 	eventRepo := postgres.NewEventRepository(dbConn)
+	eventHostRepo := postgres.NewEventHostRepository(dbConn)
 	volunteerAppRepo := postgres.NewVolunteerApplicationRepository(dbConn)
 	eventVolunteerRepo := postgres.NewEventVolunteerRepository(dbConn)
+	mealLogRepo := postgres.NewMealLogRepository(dbConn)
+	inventoryRepo := postgres.NewInventoryRepository(dbConn)
+	inventoryConsumptionRepo := postgres.NewInventoryConsumptionRepository(dbConn)
+	restaurantDocumentRepo := postgres.NewRestaurantDocumentRepository(dbConn)
+	restaurantMemberRepo := postgres.NewRestaurantMemberRepository(dbConn)
+	restaurantInvitationRepo := postgres.NewRestaurantInvitationRepository(dbConn)
+	loginAttemptRepo := postgres.NewLoginAttemptRepository(dbConn)
+	recoveryCodeRepo := postgres.NewRecoveryCodeRepository(dbConn)
+	securityPolicyRepo := postgres.NewSecurityPolicyRepository(dbConn)
+	externalIdentityRepo := postgres.NewExternalIdentityRepository(dbConn)
+	apiKeyRepo := postgres.NewAPIKeyRepository(dbConn)
+	auditLogRepo := postgres.NewAuditLogRepository(dbConn)
 	tokenCache := redis.NewTokenCache(redisConn)
+	oneTimeTokenStore := redis.NewOneTimeTokenStore(redisConn)
+	throttle := redis.NewThrottle(redisConn)
+	attemptCounter := redis.NewAttemptCounter(redisConn)
 
-	jwtService := jwt.NewService(cfg.JWT.Secret, cfg.JWT.ExpiresIn)
-
-	authService := application.NewAuthService(txManager, userRepo, restaurantRepo, volunteerRepo, tokenCache, jwtService)
-	userService := application.NewUserService(txManager, userRepo, restaurantRepo, volunteerRepo)
-	restaurantService := application.NewRestaurantService(txManager, restaurantRepo, eventRepo, volunteerRepo, volunteerAppRepo, eventVolunteerRepo)
-	eventService := application.NewEventService(txManager, eventRepo, restaurantRepo)
-	volunteerService := application.NewVolunteerService(txManager, volunteerRepo, volunteerAppRepo, eventVolunteerRepo, eventRepo, restaurantRepo)
+	mailer, err := mail.NewMailer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up the mailer: %v", err)
+	}
+	signingKeys, err := jwt.LoadKeySet(cfg.JWT.KeysDir)
+	if err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	jwtService := jwt.NewService(signingKeys, cfg.JWT.ExpiresIn)
+
+	identityProviders := oidc.NewProviders(cfg)
+
+	loginLimits := application.LoginLimits{
+		Window:             cfg.Login.Window,
+		MaxAccountFailures: cfg.Login.MaxAccountFailures,
+		MaxIPFailures:      cfg.Login.MaxIPFailures,
+		LockoutDuration:    cfg.Login.LockoutDuration,
+		DelayAfter:         cfg.Login.DelayAfter,
+		BaseDelay:          cfg.Login.BaseDelay,
+		MaxDelay:           cfg.Login.MaxDelay,
+	}
 
-	router := gin.NewRouter(
+	lockoutService := application.NewLockoutService(txManager, userRepo, loginAttemptRepo, auditLogRepo, oneTimeTokenStore, attemptCounter, loginLimits, mailer, cfg.Mail.AppURL)
+	twoFactorService := application.NewTwoFactorService(txManager, userRepo, restaurantMemberRepo, recoveryCodeRepo, securityPolicyRepo, auditLogRepo, oneTimeTokenStore, throttle, lockoutService, mailer)
+	accountService := application.NewAccountService(txManager, userRepo, restaurantRepo, auditLogRepo, tokenCache, oneTimeTokenStore, throttle, mailer, cfg.Mail.AppURL)
+	authService := application.NewAuthService(txManager, userRepo, restaurantRepo, branchRepo, volunteerRepo, restaurantMemberRepo, restaurantInvitationRepo, externalIdentityRepo, auditLogRepo, identityProviders, tokenCache, lockoutService, twoFactorService, accountService, jwtService, cfg.JWT.RefreshExpiresIn)
+	magicLinkService := application.NewMagicLinkService(txManager, userRepo, oneTimeTokenStore, throttle, attemptCounter, authService, mailer, cfg.Mail.AppURL)
+	impersonationService := application.NewImpersonationService(userRepo, restaurantRepo, volunteerRepo, auditLogRepo, tokenCache, jwtService)
+	userService := application.NewUserService(txManager, userRepo, restaurantRepo, volunteerRepo, eventRepo, volunteerAppRepo, eventVolunteerRepo, restaurantMemberRepo, externalIdentityRepo, recoveryCodeRepo, loginAttemptRepo, auditLogRepo, tokenCache, mailer, cfg.Account.DeletionGracePeriod)
+	restaurantService := application.NewRestaurantService(txManager, restaurantRepo, branchRepo, eventRepo, volunteerRepo, volunteerAppRepo, eventVolunteerRepo, inventoryRepo, inventoryConsumptionRepo)
+	eventService := application.NewEventService(txManager, eventRepo, restaurantRepo, branchRepo, eventHostRepo, mealLogRepo, auditLogRepo)
+	volunteerService := application.NewVolunteerService(txManager, volunteerRepo, volunteerAppRepo, eventVolunteerRepo, eventRepo, restaurantRepo, auditLogRepo)
+	inventoryService := application.NewInventoryService(txManager, inventoryRepo, inventoryConsumptionRepo, eventRepo)
+	adminService := application.NewAdminService(txManager, userRepo, restaurantRepo, volunteerRepo, eventRepo, branchRepo, volunteerAppRepo, eventVolunteerRepo, securityPolicyRepo, auditLogRepo, tokenCache)
+	verificationService := application.NewVerificationService(txManager, restaurantRepo, restaurantDocumentRepo, fileStorage)
+	membershipService := application.NewMembershipService(txManager, userRepo, restaurantRepo, branchRepo, restaurantMemberRepo, restaurantInvitationRepo, tokenCache, mailer, cfg.Mail.AppURL)
+	apiKeyService := application.NewAPIKeyService(txManager, apiKeyRepo, userRepo, restaurantMemberRepo, auditLogRepo)
+	auditService := application.NewAuditService(auditLogRepo, cfg.Audit.Retention)
+
+	router, err := gin.NewRouter(
 		authService,
+		accountService,
+		twoFactorService,
+		magicLinkService,
+		lockoutService,
+		impersonationService,
 		userService,
 		restaurantService,
 		eventService,
 		volunteerService,
+		inventoryService,
+		adminService,
+		verificationService,
+		membershipService,
+		apiKeyService,
+		auditService,
+		jwtService,
 		cfg,
 	)
+	if err != nil {
+		log.Fatalf("Failed to set up the router: %v", err)
+	}
 	httpServer := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
 		Handler:      router,
@@ -77,6 +149,44 @@ func main() {
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
+	// Pick up keys changed by hand and rotate the signing key once it is due. Retired keys
+	// are kept until the last access token they signed has expired.
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := signingKeys.Rotate(cfg.JWT.RotateEvery, cfg.JWT.ExpiresIn); err != nil {
+				log.Printf("Failed to rotate signing keys: %v", err)
+			}
+		}
+	}()
+
+	// Erase the accounts whose deletion grace period is over
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			purged, err := userService.PurgeDueAccounts(context.Background())
+			if err != nil {
+				log.Printf("Failed to delete accounts: %v", err)
+			}
+			if purged > 0 {
+				log.Printf("Deleted %d accounts", purged)
+			}
+		}
+	}()
+
+	// Drop audit entries past their retention period
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := auditService.PurgeExpired(context.Background()); err != nil {
+				log.Printf("Failed to purge audit log: %v", err)
+			}
+		}
+	}()
+
 	go func() {
 		log.Printf("Starting HTTP server on :%s", cfg.Server.Port)
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -0,0 +1,35 @@
+// cmd/fakeidp/main.go
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/oidc/fakeidp"
+)
+
+// Runs an OpenID Connect provider that signs everyone in, for development and integration
+// tests against the "dev" provider of config.dev.yaml:
+//
+//	go run ./cmd/fakeidp -email volunteer@example.com
+func main() {
+	addr := flag.String("addr", ":9999", "Address to listen on")
+	issuer := flag.String("issuer", "http://localhost:9999", "Issuer URL the provider is reached at")
+	email := flag.String("email", "volunteer@example.com", "Email of the signed in user, overridden by login_hint")
+	name := flag.String("name", "Test Volunteer", "Name of the signed in user")
+	unverified := flag.Bool("unverified", false, "Report the email address as not verified")
+	flag.Parse()
+
+	server, err := fakeidp.New(*issuer, fakeidp.User{
+		Email:         *email,
+		Name:          *name,
+		EmailVerified: !*unverified,
+	})
+	if err != nil {
+		log.Fatalf("Failed to start the identity provider: %v", err)
+	}
+
+	log.Printf("Fake identity provider %s listening on %s", *issuer, *addr)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
@@ -6,9 +6,16 @@ import (
 
 type Config struct {
 	Server   ServerConfig
+	HTTP     HTTPConfig
 	Database DatabaseConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
+	Storage  StorageConfig
+	Mail     MailConfig
+	Login    LoginConfig
+	OIDC     OIDCConfig
+	Account  AccountConfig
+	Audit    AuditConfig
 	CORS     struct {
 		AllowedOrigins []string `yaml:"allowedOrigins"`
 	} `yaml:"cors"`
@@ -22,6 +29,14 @@ type ServerConfig struct {
 	ShutdownTimeout time.Duration
 }
 
+// HTTPConfig holds the settings of the HTTP adapter
+type HTTPConfig struct {
+	// TrustedProxies are the addresses or CIDR ranges of the reverse proxies in front of
+	// the API. Only their X-Forwarded-For headers are believed; with none, the client IP
+	// is the address of the connection.
+	TrustedProxies []string
+}
+
 type DatabaseConfig struct {
 	Host     string
 	Port     string
@@ -38,8 +53,60 @@ type RedisConfig struct {
 }
 
 type JWTConfig struct {
-	Secret    string
-	ExpiresIn time.Duration
+	KeysDir          string        // directory of the PEM signing keys, see jwt.KeySet
+	RotateEvery      time.Duration // age at which a new signing key is generated, zero disables rotation
+	ExpiresIn        time.Duration // lifetime of access tokens
+	RefreshExpiresIn time.Duration // lifetime of refresh tokens, a session ends when its refresh token expires unused
+}
+
+type StorageConfig struct {
+	LocalPath     string
+	MaxUploadSize int64
+}
+
+type MailConfig struct {
+	Host      string
+	Port      int
+	Username  string
+	Password  string
+	From      string
+	AppURL    string // base URL of the web app, used to build links in emails
+	OutboxDir string // without a host, emails are written as files to this directory when set
+}
+
+// LoginConfig limits failed sign-ins, counted per account and per IP address over a
+// sliding window
+type LoginConfig struct {
+	Window             time.Duration // how long a failed attempt counts
+	MaxAccountFailures int           // failures that lock the account
+	MaxIPFailures      int           // failures after which an IP address is refused
+	LockoutDuration    time.Duration // how long a locked account stays locked
+	DelayAfter         int           // failures after which each further attempt must wait
+	BaseDelay          time.Duration // first wait, doubled with every further failure
+	MaxDelay           time.Duration
+}
+
+// OIDCConfig lists the OpenID Connect providers users can sign in with
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+}
+
+type OIDCProviderConfig struct {
+	Name         string // used in the sign-in URLs, /auth/oidc/<name>
+	Issuer       string // the provider's issuer URL, its discovery document is read from there
+	ClientID     string
+	ClientSecret string   // may be empty for public clients, PKCE protects the code either way
+	RedirectURL  string   // must point to /api/v1/auth/oidc/<name>/callback
+	Scopes       []string // openid, email and profile when empty
+}
+
+// AccountConfig controls the self-service deletion of accounts
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration // time during which a requested deletion can still be cancelled
+}
+
+type AuditConfig struct {
+	Retention time.Duration // how long audit entries are kept, zero keeps them forever
 }
 
 type CookieConfig struct {
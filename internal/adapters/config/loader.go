@@ -51,6 +51,7 @@ func LoadConfig(path string) (*Config, error) {
 	v.SetDefault("server.readTimeout", time.Second*10)
 	v.SetDefault("server.writeTimeout", time.Second*10)
 	v.SetDefault("server.shutdownTimeout", time.Second*30)
+	v.SetDefault("server.environment", env)
 	v.SetDefault("database.host", "localhost")
 	v.SetDefault("database.port", "5432")
 	v.SetDefault("database.user", "postgres")
@@ -58,11 +59,24 @@ func LoadConfig(path string) (*Config, error) {
 	v.SetDefault("redis.address", "localhost:6379")
 	v.SetDefault("redis.password", "")
 	v.SetDefault("redis.db", 0)
-	v.SetDefault("jwt.expiresIn", time.Hour*24)
-
-	if !v.IsSet("jwt.secret") {
-		return nil, fmt.Errorf("jwt secret is required")
-	}
+	v.SetDefault("jwt.keysDir", "./data/keys")
+	v.SetDefault("jwt.rotateEvery", time.Hour*24*30)
+	v.SetDefault("jwt.expiresIn", time.Minute*15)
+	v.SetDefault("jwt.refreshExpiresIn", time.Hour*24*7)
+	v.SetDefault("storage.localPath", "./data/uploads")
+	v.SetDefault("storage.maxUploadSize", 10<<20)
+	v.SetDefault("mail.port", 587)
+	v.SetDefault("mail.from", "no-reply@localhost")
+	v.SetDefault("mail.appURL", "http://localhost:3000")
+	v.SetDefault("login.window", time.Minute*15)
+	v.SetDefault("login.maxAccountFailures", 10)
+	v.SetDefault("login.maxIPFailures", 50)
+	v.SetDefault("login.lockoutDuration", time.Minute*30)
+	v.SetDefault("login.delayAfter", 3)
+	v.SetDefault("login.baseDelay", time.Second)
+	v.SetDefault("login.maxDelay", time.Minute)
+	v.SetDefault("account.deletionGracePeriod", time.Hour*24*30)
+	v.SetDefault("audit.retention", time.Hour*24*365)
 
 	var config Config
 	if err := v.Unmarshal(&config); err != nil {
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminService ports.AdminService
+}
+
+func NewAdminHandler(adminService ports.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var filter domain.ListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	users, total, err := h.adminService.ListUsers(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": users, "total": total})
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, profile, err := h.adminService.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user, "profile": profile})
+}
+
+func (h *AdminHandler) UpdateUser(c *gin.Context) {
+	var req domain.AdminUserUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	user, err := h.adminService.UpdateUser(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	var req domain.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.adminService.SuspendUser(c.Request.Context(), c.Param("id"), req.Reason); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user suspended successfully"})
+}
+
+func (h *AdminHandler) ReactivateUser(c *gin.Context) {
+	if err := h.adminService.ReactivateUser(c.Request.Context(), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user reactivated successfully"})
+}
+
+func (h *AdminHandler) ListRestaurants(c *gin.Context) {
+	var filter domain.ListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	restaurants, total, err := h.adminService.ListRestaurants(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": restaurants, "total": total})
+}
+
+func (h *AdminHandler) GetRestaurant(c *gin.Context) {
+	restaurant, err := h.adminService.GetRestaurant(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, restaurant)
+}
+
+func (h *AdminHandler) UpdateRestaurant(c *gin.Context) {
+	var req domain.AdminRestaurantUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	restaurant, err := h.adminService.UpdateRestaurant(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, restaurant)
+}
+
+func (h *AdminHandler) ListEvents(c *gin.Context) {
+	var filter domain.ListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	events, total, err := h.adminService.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": events, "total": total})
+}
+
+func (h *AdminHandler) UpdateEvent(c *gin.Context) {
+	var req domain.AdminEventUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	event, err := h.adminService.UpdateEvent(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+func (h *AdminHandler) ListApplications(c *gin.Context) {
+	var filter domain.ListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	applications, total, err := h.adminService.ListApplications(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": applications, "total": total})
+}
+
+func (h *AdminHandler) UpdateApplicationStatus(c *gin.Context) {
+	var req struct {
+		Status string `json:"status" binding:"required,oneof=pending approved declined"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.adminService.UpdateApplicationStatus(c.Request.Context(), c.Param("id"), req.Status); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "application status updated successfully"})
+}
+
+func (h *AdminHandler) GetSecurityPolicy(c *gin.Context) {
+	policy, err := h.adminService.GetSecurityPolicy(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *AdminHandler) UpdateSecurityPolicy(c *gin.Context) {
+	var req domain.UpdateSecurityPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	policy, err := h.adminService.UpdateSecurityPolicy(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
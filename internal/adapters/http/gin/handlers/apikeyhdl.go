@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyService ports.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService ports.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.apiKeyService.ListAPIKeys(c.Request.Context(), restaurant.ID.String())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// CreateAPIKey returns the new key, the only time it is shown
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(c.Request.Context(), restaurant.ID.String(), c.GetString("user_id"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(c.Request.Context(), restaurant.ID.String(), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
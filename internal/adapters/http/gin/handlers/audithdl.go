@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService ports.AuditService
+}
+
+func NewAuditHandler(auditService ports.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditLogs returns the audit entries matching the query, newest first
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	var filter domain.AuditLogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	entries, total, err := h.auditService.ListAuditLogs(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": entries, "total": total})
+}
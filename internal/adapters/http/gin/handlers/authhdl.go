@@ -1,25 +1,68 @@
 package handlers
 
 import (
-	"fmt"
+	"crypto/subtle"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin/middleware"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/token"
 	"github.com/gin-gonic/gin"
 )
 
+// refreshCookiePath limits the refresh token cookie to the endpoints that consume it
+const refreshCookiePath = "/api/v1/auth"
+
+const (
+	// oidcFlowCookie keeps the state, nonce and PKCE verifier of a provider sign-in in the
+	// browser that started it, so the callback cannot be replayed elsewhere
+	oidcFlowCookie     = "oidc_flow"
+	oidcFlowCookiePath = "/api/v1/auth/oidc"
+	oidcFlowTTL        = 10 * time.Minute
+
+	// magicLinkCookie keeps the device binding of a requested sign-in link in the browser
+	// that requested it
+	magicLinkCookie     = "magic_link_binding"
+	magicLinkCookiePath = "/api/v1/auth/magic-link"
+
+	// twoFactorChallengeCookie hands the challenge of a provider sign-in to the two-factor
+	// step, a URL would leak it to the browser history, Referer headers and proxy logs
+	twoFactorChallengeCookie     = "two_factor_challenge"
+	twoFactorChallengeCookiePath = "/api/v1/auth/login/two_factor"
+)
+
 type AuthHandler struct {
-	authService ports.AuthService
-	config      *config.Config
+	authService          ports.AuthService
+	accountService       ports.AccountService
+	twoFactorService     ports.TwoFactorService
+	magicLinkService     ports.MagicLinkService
+	lockoutService       ports.LockoutService
+	impersonationService ports.ImpersonationService
+	config               *config.Config
 }
 
-func NewAuthHandler(authService ports.AuthService, config *config.Config) *AuthHandler {
+func NewAuthHandler(
+	authService ports.AuthService,
+	accountService ports.AccountService,
+	twoFactorService ports.TwoFactorService,
+	magicLinkService ports.MagicLinkService,
+	lockoutService ports.LockoutService,
+	impersonationService ports.ImpersonationService,
+	config *config.Config,
+) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		config:      config,
+		authService:          authService,
+		accountService:       accountService,
+		twoFactorService:     twoFactorService,
+		magicLinkService:     magicLinkService,
+		lockoutService:       lockoutService,
+		impersonationService: impersonationService,
+		config:               config,
 	}
 }
 
@@ -28,53 +71,158 @@ func NewAuthHandler(authService ports.AuthService, config *config.Config) *AuthH
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req domain.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	res, token, err := h.authService.Login(c.Request.Context(), req)
+	res, tokens, challenge, err := h.authService.Login(c.Request.Context(), req, deviceInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	h.setAuthCookie(c, token, time.Now().Add(time.Hour*24*7))
-	c.JSON(http.StatusOK, res)
+	// The password was right, the sign-in continues at /auth/login/two_factor
+	if challenge != nil {
+		c.JSON(http.StatusOK, challenge)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, res, tokens, wantsTokensInBody(c))
 }
 
-func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	token, err := c.Cookie("auth_token")
+// StartOIDCLogin sends the browser to the identity provider
+func (h *AuthHandler) StartOIDCLogin(c *gin.Context) {
+	auth, err := h.authService.StartOIDCLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	secure := h.config.Server.Environment == "prod"
+	flow := strings.Join([]string{auth.State, auth.Nonce, auth.CodeVerifier}, ".")
+	c.SetCookie(oidcFlowCookie, flow, int(oidcFlowTTL.Seconds()), oidcFlowCookiePath, "", secure, true)
+
+	c.Redirect(http.StatusFound, auth.URL)
+}
+
+// OIDCCallback is where the identity provider sends the browser back to. It signs the user in
+// with cookies and returns to the web app, errors are passed on in the error parameter.
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	secure := h.config.Server.Environment == "prod"
+	flow, err := c.Cookie(oidcFlowCookie)
+	c.SetCookie(oidcFlowCookie, "", -1, oidcFlowCookiePath, "", secure, true)
+
+	parts := strings.Split(flow, ".")
+	if err != nil || len(parts) != 3 || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(c.Query("state"))) != 1 {
+		h.redirectToApp(c, "/login", url.Values{"error": {"the sign-in expired, please try again"}})
+		return
+	}
+
+	if providerError := c.Query("error"); providerError != "" {
+		h.redirectToApp(c, "/login", url.Values{"error": {providerError}})
+		return
+	}
+
+	callback := domain.OIDCCallback{
+		Code:         c.Query("code"),
+		Nonce:        parts[1],
+		CodeVerifier: parts[2],
+	}
+	res, tokens, challenge, err := h.authService.CompleteOIDCLogin(c.Request.Context(), c.Param("provider"), callback, deviceInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		h.redirectToApp(c, "/login", url.Values{"error": {middleware.ErrorMessage(err)}})
 		return
 	}
 
-	res, newToken, err := h.authService.RefreshToken(c.Request.Context(), token)
+	if challenge != nil {
+		secure := h.config.Server.Environment == "prod"
+		c.SetCookie(twoFactorChallengeCookie, challenge.ChallengeToken, int(time.Until(challenge.ExpiresAt).Seconds()), twoFactorChallengeCookiePath, "", secure, true)
+		h.redirectToApp(c, "/login/two-factor", nil)
+		return
+	}
+
+	csrfToken, err := token.Generate()
 	if err != nil {
-		c.SetCookie("auth_token", "", -1, "/", "", false, true)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		h.redirectToApp(c, "/login", url.Values{"error": {middleware.ErrorMessage(err)}})
 		return
 	}
 
-	h.setAuthCookie(c, newToken, res.ExpiresAt)
+	h.setAuthCookies(c, tokens, res, csrfToken)
+	h.redirectToApp(c, "/", nil)
+}
 
-	c.JSON(http.StatusOK, res)
+func (h *AuthHandler) redirectToApp(c *gin.Context, path string, query url.Values) {
+	target := strings.TrimRight(h.config.Mail.AppURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	c.Redirect(http.StatusFound, target)
 }
 
-func (h *AuthHandler) Logout(c *gin.Context) {
-	token, err := c.Cookie("auth_token")
-	if err != nil {
-		c.JSON(http.StatusOK, gin.H{"message": "already logged out"})
+func (h *AuthHandler) CompleteTwoFactorLogin(c *gin.Context) {
+	var req domain.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	err = h.authService.Logout(c.Request.Context(), token)
+	// Provider sign-ins leave the challenge in a cookie
+	if req.ChallengeToken == "" {
+		req.ChallengeToken, _ = c.Cookie(twoFactorChallengeCookie)
+	}
+
+	res, tokens, err := h.authService.CompleteTwoFactorLogin(c.Request.Context(), req, deviceInfo(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
+
 	secure := h.config.Server.Environment == "prod"
-	c.SetCookie("auth_token", "", -1, "/", "", secure, true)
+	c.SetCookie(twoFactorChallengeCookie, "", -1, twoFactorChallengeCookiePath, "", secure, true)
+
+	h.respondWithTokens(c, http.StatusOK, res, tokens, wantsTokensInBody(c))
+}
+
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	// Browsers send the refresh token as a cookie, other clients in the body
+	refreshToken, err := c.Cookie(middleware.RefreshCookie)
+	inBody := err != nil
+	if inBody {
+		var req domain.RefreshTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(domain.ErrAuthenticationRequired)
+			return
+		}
+		refreshToken = req.RefreshToken
+	}
+
+	res, tokens, err := h.authService.RefreshToken(c.Request.Context(), refreshToken)
+	if err != nil {
+		if !inBody {
+			h.clearAuthCookies(c)
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, res, tokens, inBody)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	refreshToken, err := c.Cookie(middleware.RefreshCookie)
+	if err != nil {
+		var req domain.RefreshTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.clearAuthCookies(c)
+			c.JSON(http.StatusOK, gin.H{"message": "already logged out"})
+			return
+		}
+		refreshToken = req.RefreshToken
+	}
+
+	// The cookies go either way, an unknown refresh token has no session left to end
+	_ = h.authService.Logout(c.Request.Context(), refreshToken)
+	h.clearAuthCookies(c)
 
 	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 }
@@ -84,46 +232,440 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 func (h *AuthHandler) RegisterRestaurant(c *gin.Context) {
 	var req domain.RestaurantRegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	res, token, err := h.authService.RegisterRestaurant(c.Request.Context(), req)
+	res, tokens, err := h.authService.RegisterRestaurant(c.Request.Context(), req, deviceInfo(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
-	h.setAuthCookie(c, token, res.ExpiresAt)
-	c.JSON(http.StatusCreated, res)
+	h.respondWithTokens(c, http.StatusCreated, res, tokens, wantsTokensInBody(c))
+}
+
+func (h *AuthHandler) RegisterOrganization(c *gin.Context) {
+	var req domain.OrganizationRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, tokens, err := h.authService.RegisterOrganization(c.Request.Context(), req, deviceInfo(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.respondWithTokens(c, http.StatusCreated, res, tokens, wantsTokensInBody(c))
 }
 
 func (h *AuthHandler) RegisterVolunteer(c *gin.Context) {
 	var req domain.VolunteerRegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, tokens, err := h.authService.RegisterVolunteer(c.Request.Context(), req, deviceInfo(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.respondWithTokens(c, http.StatusCreated, res, tokens, wantsTokensInBody(c))
+}
+
+func (h *AuthHandler) RegisterStaff(c *gin.Context) {
+	var req domain.StaffRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, tokens, err := h.authService.RegisterStaff(c.Request.Context(), req, deviceInfo(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.respondWithTokens(c, http.StatusCreated, res, tokens, wantsTokensInBody(c))
+}
+
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req domain.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// The response is the same whether or not an account exists for the address
+	c.JSON(http.StatusOK, gin.H{"message": "if an account exists for this email, a reset link has been sent"})
+}
+
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req domain.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	binding, expiresAt, err := h.magicLinkService.RequestMagicLink(c.Request.Context(), req.Email, deviceInfo(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// The response is the same whether or not a volunteer account exists for the address
+	res := gin.H{"message": "if a volunteer account exists for this email, a sign-in link has been sent"}
+	if wantsTokensInBody(c) {
+		res["device_binding"] = binding
+	} else {
+		secure := h.config.Server.Environment == "prod"
+		c.SetCookie(magicLinkCookie, binding, int(time.Until(expiresAt).Seconds()), magicLinkCookiePath, "", secure, true)
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *AuthHandler) VerifyMagicLink(c *gin.Context) {
+	var req domain.MagicLinkVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	binding, err := c.Cookie(magicLinkCookie)
+	if err != nil {
+		binding = req.DeviceBinding
+	}
+
+	res, tokens, challenge, err := h.magicLinkService.ConsumeMagicLink(c.Request.Context(), req.Token, binding, deviceInfo(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	secure := h.config.Server.Environment == "prod"
+	c.SetCookie(magicLinkCookie, "", -1, magicLinkCookiePath, "", secure, true)
+
+	if challenge != nil {
+		c.JSON(http.StatusOK, challenge)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, res, tokens, wantsTokensInBody(c))
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req domain.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.accountService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password reset successfully"})
+}
+
+func (h *AuthHandler) UnlockAccount(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.lockoutService.UnlockAccount(c.Request.Context(), req.Token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account unlocked successfully"})
+}
+
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.accountService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email verified successfully"})
+}
+
+func (h *AuthHandler) VerifyBusinessEmail(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.accountService.VerifyBusinessEmail(c.Request.Context(), req.Token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "business email verified successfully"})
+}
+
+func (h *AuthHandler) ResendVerificationEmail(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := h.accountService.SendVerificationEmail(c.Request.Context(), userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "verification email sent successfully"})
+}
+
+func (h *AuthHandler) ResendBusinessVerificationEmail(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	if err := h.accountService.SendBusinessVerificationEmail(c.Request.Context(), restaurant.ID.String()); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "verification email sent successfully"})
+}
+
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req domain.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.accountService.ChangePassword(c.Request.Context(), c.GetString("user_id"), c.GetString("session_id"), req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password changed successfully"})
+}
+
+func (h *AuthHandler) ChangeEmail(c *gin.Context) {
+	var req domain.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.accountService.RequestEmailChange(c.Request.Context(), c.GetString("user_id"), req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "a confirmation link has been sent to the new email address"})
+}
+
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.accountService.ConfirmEmailChange(c.Request.Context(), req.Token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email changed successfully"})
+}
+
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	var req domain.TwoFactorSetupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	setup, err := h.twoFactorService.SetupTwoFactor(c.Request.Context(), c.GetString("user_id"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+func (h *AuthHandler) EnableTwoFactor(c *gin.Context) {
+	var req domain.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	codes, err := h.twoFactorService.EnableTwoFactor(c.Request.Context(), c.GetString("user_id"), req.Code)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication enabled successfully", "recovery_codes": codes})
+}
+
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var req domain.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.twoFactorService.DisableTwoFactor(c.Request.Context(), c.GetString("user_id"), req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled successfully"})
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req domain.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), c.GetString("user_id"), req.Code)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	res, token, err := h.authService.RegisterVolunteer(c.Request.Context(), req)
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	sessions, err := h.authService.ListSessions(c.Request.Context(), c.GetString("user_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions":           sessions,
+		"current_session_id": c.GetString("session_id"),
+	})
+}
+
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	sessionID := c.Param("id")
+
+	if sessionID == c.GetString("session_id") {
+		_ = c.Error(domain.ErrCurrentSession)
 		return
 	}
-	h.setAuthCookie(c, token, res.ExpiresAt)
-	c.JSON(http.StatusCreated, res)
+
+	if err := h.authService.RevokeSession(c.Request.Context(), c.GetString("user_id"), sessionID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked successfully"})
 }
 
-func (h *AuthHandler) setAuthCookie(c *gin.Context, token domain.Token, expiresAt time.Time) {
-	fmt.Println("h.config.Server.Environment", h.config.Server.Environment)
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	if err := h.authService.RevokeOtherSessions(c.Request.Context(), c.GetString("user_id"), c.GetString("session_id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "other sessions revoked successfully"})
+}
+
+// Impersonate opens a session as another user for the administrator making the request.
+// The access token is always returned in the body, cookies would replace the
+// administrator's own session in the browser.
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	var req domain.ImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	res, tokens, err := h.impersonationService.StartImpersonation(c.Request.Context(), c.GetString("user_id"), c.Param("id"), req, deviceInfo(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res.AccessToken = tokens.AccessToken.String()
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *AuthHandler) EndImpersonation(c *gin.Context) {
+	if err := h.impersonationService.EndImpersonation(c.Request.Context(), c.GetString("user_id"), c.GetString("session_id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "impersonation ended successfully"})
+}
+
+// deviceInfo describes the client of the request for the session it opens
+func deviceInfo(c *gin.Context) domain.DeviceInfo {
+	return domain.DeviceInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// wantsTokensInBody reports whether the client asked for the tokens in the response body,
+// to send them back as bearer tokens, instead of as cookies
+func wantsTokensInBody(c *gin.Context) bool {
+	return c.Query("token_delivery") == "body"
+}
+
+// respondWithTokens hands the tokens out in the body or as cookies. Cookie clients also get
+// the CSRF token they must echo in the X-CSRF-Token header.
+func (h *AuthHandler) respondWithTokens(c *gin.Context, status int, res *domain.AuthResponse, tokens domain.TokenPair, inBody bool) {
+	if inBody {
+		res.AccessToken = tokens.AccessToken.String()
+		res.RefreshToken = tokens.RefreshToken.String()
+		c.JSON(status, res)
+		return
+	}
+
+	csrfToken, err := token.Generate()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.setAuthCookies(c, tokens, res, csrfToken)
+	res.CSRFToken = csrfToken
+	c.JSON(status, res)
+}
+
+// setAuthCookies stores the access token for every request and the refresh token only for
+// the auth endpoints that use it. The CSRF cookie is readable by scripts on purpose.
+func (h *AuthHandler) setAuthCookies(c *gin.Context, tokens domain.TokenPair, res *domain.AuthResponse, csrfToken string) {
 	secure := h.config.Server.Environment == "prod"
-	maxAge := int(time.Until(expiresAt).Seconds())
-	c.SetCookie(
-		"auth_token",
-		token.String(),
-		maxAge,
-		"/",
-		"",
-		secure,
-		true,
-	)
+	c.SetCookie("auth_token", tokens.AccessToken.String(), int(time.Until(res.ExpiresAt).Seconds()), "/", "", secure, true)
+	c.SetCookie(middleware.RefreshCookie, tokens.RefreshToken.String(), int(time.Until(res.RefreshExpiresAt).Seconds()), refreshCookiePath, "", secure, true)
+	c.SetCookie(middleware.CSRFCookie, csrfToken, int(time.Until(res.RefreshExpiresAt).Seconds()), "/", "", secure, false)
+}
+
+func (h *AuthHandler) clearAuthCookies(c *gin.Context) {
+	secure := h.config.Server.Environment == "prod"
+	c.SetCookie("auth_token", "", -1, "/", "", secure, true)
+	c.SetCookie(middleware.RefreshCookie, "", -1, refreshCookiePath, "", secure, true)
+	c.SetCookie(middleware.CSRFCookie, "", -1, "/", "", secure, false)
 }
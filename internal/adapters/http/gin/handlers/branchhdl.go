@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BranchHandler struct {
+	restaurantService ports.RestaurantService
+}
+
+func NewBranchHandler(restaurantService ports.RestaurantService) *BranchHandler {
+	return &BranchHandler{
+		restaurantService: restaurantService,
+	}
+}
+
+func (h *BranchHandler) GetBranches(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	branches, err := h.restaurantService.GetBranches(c.Request.Context(), restaurant.ID.String())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, branches)
+}
+
+func (h *BranchHandler) CreateBranch(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	var branch domain.Branch
+	if err := c.ShouldBindJSON(&branch); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	branch.ID = uuid.Nil
+	branch.RestaurantID = restaurant.ID
+
+	if err := h.restaurantService.CreateBranch(c.Request.Context(), &branch); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, branch)
+}
+
+func (h *BranchHandler) UpdateBranch(c *gin.Context) {
+	branch, ok := h.ownBranch(c)
+	if !ok {
+		return
+	}
+
+	var updatedBranch domain.Branch
+	if err := c.ShouldBindJSON(&updatedBranch); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Preserve the ID, restaurant ID and creation time
+	updatedBranch.ID = branch.ID
+	updatedBranch.RestaurantID = branch.RestaurantID
+	updatedBranch.CreatedAt = branch.CreatedAt
+
+	if err := h.restaurantService.UpdateBranch(c.Request.Context(), &updatedBranch); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedBranch)
+}
+
+func (h *BranchHandler) DeleteBranch(c *gin.Context) {
+	branch, ok := h.ownBranch(c)
+	if !ok {
+		return
+	}
+
+	if err := h.restaurantService.DeleteBranch(c.Request.Context(), branch.ID.String()); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "branch deleted successfully"})
+}
+
+// ownBranch loads the branch addressed by the request and checks it belongs to the
+// restaurant the user acts for, writing the error response itself when it does not
+func (h *BranchHandler) ownBranch(c *gin.Context) (*domain.Branch, bool) {
+	branch, err := h.restaurantService.GetBranch(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return nil, false
+	}
+
+	// Verify ownership
+	if branch.RestaurantID != restaurant.ID {
+		_ = c.Error(permissionDenied("manage this branch"))
+		return nil, false
+	}
+
+	return branch, true
+}
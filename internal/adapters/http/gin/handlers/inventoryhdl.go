@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InventoryHandler struct {
+	inventoryService ports.InventoryService
+	eventService     ports.EventService
+}
+
+func NewInventoryHandler(
+	inventoryService ports.InventoryService,
+	eventService ports.EventService,
+) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+		eventService:     eventService,
+	}
+}
+
+func (h *InventoryHandler) GetInventory(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.inventoryService.GetInventory(c.Request.Context(), restaurant.ID.String())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *InventoryHandler) AddItem(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	var item domain.InventoryItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	item.ID = uuid.Nil
+	item.RestaurantID = restaurant.ID
+
+	if err := h.inventoryService.AddItem(c.Request.Context(), &item); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *InventoryHandler) UpdateItem(c *gin.Context) {
+	itemID := c.Param("id")
+
+	item, err := h.inventoryService.GetItem(c.Request.Context(), itemID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if item.RestaurantID != restaurant.ID {
+		_ = c.Error(permissionDenied("update this inventory item"))
+		return
+	}
+
+	var updatedItem domain.InventoryItem
+	if err := c.ShouldBindJSON(&updatedItem); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	// Preserve the ID, restaurant ID and creation time
+	updatedItem.ID = item.ID
+	updatedItem.RestaurantID = item.RestaurantID
+	updatedItem.CreatedAt = item.CreatedAt
+
+	if err := h.inventoryService.UpdateItem(c.Request.Context(), &updatedItem); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedItem)
+}
+
+func (h *InventoryHandler) DeleteItem(c *gin.Context) {
+	itemID := c.Param("id")
+
+	item, err := h.inventoryService.GetItem(c.Request.Context(), itemID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if item.RestaurantID != restaurant.ID {
+		_ = c.Error(permissionDenied("delete this inventory item"))
+		return
+	}
+
+	if err := h.inventoryService.DeleteItem(c.Request.Context(), itemID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "inventory item deleted successfully"})
+}
+
+func (h *InventoryHandler) GetExpiringItems(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	// Defaults to the service's alert window when not provided
+	var within time.Duration
+	if days := c.Query("days"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			_ = c.Error(domain.NewValidationError(domain.ErrCodeInvalidRequest, "days must be a positive integer"))
+			return
+		}
+		within = time.Duration(n) * 24 * time.Hour
+	}
+
+	items, err := h.inventoryService.GetExpiringItems(c.Request.Context(), restaurant.ID.String(), within)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *InventoryHandler) RecordConsumption(c *gin.Context) {
+	eventID := c.Param("id")
+
+	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	member, ok := currentMembership(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if event.RestaurantID != member.RestaurantID || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("update this event"))
+		return
+	}
+
+	var req struct {
+		InventoryItemID string  `json:"inventory_item_id" binding:"required"`
+		Quantity        float64 `json:"quantity" binding:"required,gt=0"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	consumption, err := h.inventoryService.RecordConsumption(c.Request.Context(), eventID, req.InventoryItemID, req.Quantity)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, consumption)
+}
+
+func (h *InventoryHandler) GetEventSummary(c *gin.Context) {
+	eventID := c.Param("id")
+
+	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	member, ok := currentMembership(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if event.RestaurantID != member.RestaurantID || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("view this event"))
+		return
+	}
+
+	summary, err := h.inventoryService.GetEventSummary(c.Request.Context(), eventID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
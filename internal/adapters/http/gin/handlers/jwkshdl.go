@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	jwtService *jwt.Service
+}
+
+func NewJWKSHandler(jwtService *jwt.Service) *JWKSHandler {
+	return &JWKSHandler{
+		jwtService: jwtService,
+	}
+}
+
+// GetJWKS publishes the public keys tokens are signed with, so other services can verify
+// them without sharing a secret
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.JWKS())
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type MembershipHandler struct {
+	membershipService ports.MembershipService
+}
+
+func NewMembershipHandler(membershipService ports.MembershipService) *MembershipHandler {
+	return &MembershipHandler{
+		membershipService: membershipService,
+	}
+}
+
+func (h *MembershipHandler) GetMembers(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.membershipService.GetMembers(c.Request.Context(), restaurant.ID.String())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+func (h *MembershipHandler) UpdateMember(c *gin.Context) {
+	if _, ok := h.ownMember(c); !ok {
+		return
+	}
+
+	var req domain.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.membershipService.UpdateMember(c.Request.Context(), c.Param("id"), req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member updated successfully"})
+}
+
+func (h *MembershipHandler) RemoveMember(c *gin.Context) {
+	if _, ok := h.ownMember(c); !ok {
+		return
+	}
+
+	if err := h.membershipService.RemoveMember(c.Request.Context(), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed successfully"})
+}
+
+func (h *MembershipHandler) InviteMember(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	var req domain.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	invitation, err := h.membershipService.InviteMember(c.Request.Context(), restaurant.ID.String(), c.GetString("user_id"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+func (h *MembershipHandler) GetInvitations(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.membershipService.GetInvitations(c.Request.Context(), restaurant.ID.String())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (h *MembershipHandler) RevokeInvitation(c *gin.Context) {
+	invitation, err := h.membershipService.GetInvitation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if invitation.RestaurantID != restaurant.ID {
+		_ = c.Error(permissionDenied("revoke this invitation"))
+		return
+	}
+
+	if err := h.membershipService.RevokeInvitation(c.Request.Context(), invitation.ID.String()); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation revoked successfully"})
+}
+
+func (h *MembershipHandler) GetMemberships(c *gin.Context) {
+	memberships, err := h.membershipService.GetMemberships(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, memberships)
+}
+
+func (h *MembershipHandler) AcceptInvitation(c *gin.Context) {
+	var req domain.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	member, err := h.membershipService.AcceptInvitation(c.Request.Context(), c.GetString("user_id"), req.Token)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// ownMember loads the member addressed by the request and checks it belongs to the
+// restaurant the user acts for, writing the error response itself when it does not
+func (h *MembershipHandler) ownMember(c *gin.Context) (*domain.RestaurantMember, bool) {
+	member, err := h.membershipService.GetMember(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return nil, false
+	}
+
+	// Verify ownership
+	if member.RestaurantID != restaurant.ID {
+		_ = c.Error(permissionDenied("manage this member"))
+		return nil, false
+	}
+
+	return member, true
+}
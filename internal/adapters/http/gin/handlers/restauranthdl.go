@@ -27,47 +27,48 @@ func NewRestaurantHandler(
 }
 
 func (h *RestaurantHandler) GetDashboard(c *gin.Context) {
-	// Get user from context (set by auth middleware)
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	// Get the restaurant the user acts for
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
 		return
 	}
 
-	// Get restaurant by user ID
-	restaurant, err := h.restaurantService.GetRestaurantByUserID(c.Request.Context(), user.(*domain.User).ID.String())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	member, ok := currentMembership(c)
+	if !ok {
 		return
 	}
 
+	// Limit the dashboard to a single branch when requested or required by the membership
+	branchID := branchScope(c, member)
+
 	// Get restaurant stats
-	stats, err := h.restaurantService.GetRestaurantStats(c.Request.Context(), restaurant.ID.String())
+	stats, err := h.restaurantService.GetRestaurantStats(c.Request.Context(), restaurant.ID.String(), branchID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	// Get upcoming events
-	upcomingEvents, _, err := h.eventService.GetUpcomingEvents(c.Request.Context(), restaurant.ID.String(), 5, 0)
+	upcomingEvents, _, err := h.eventService.GetUpcomingEvents(c.Request.Context(), restaurant.ID.String(), branchID, 5, 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	// Get today's events
-	todayEvents, err := h.eventService.GetTodayEvents(c.Request.Context(), restaurant.ID.String())
+	todayEvents, err := h.eventService.GetTodayEvents(c.Request.Context(), restaurant.ID.String(), branchID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	// Get pending volunteer applications
 	pendingApps, err := h.volunteerService.GetPendingApplications(c.Request.Context(), restaurant.ID.String())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
+	pendingApps = branchApplications(pendingApps, branchID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"restaurant":      restaurant,
@@ -79,47 +80,48 @@ func (h *RestaurantHandler) GetDashboard(c *gin.Context) {
 }
 
 func (h *RestaurantHandler) GetRestaurant(c *gin.Context) {
-	// Get user from context (set by auth middleware)
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	// Get the restaurant the user acts for
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
 		return
 	}
 
-	// Get restaurant by user ID
-	restaurant, err := h.restaurantService.GetRestaurantByUserID(c.Request.Context(), user.(*domain.User).ID.String())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	member, ok := currentMembership(c)
+	if !ok {
 		return
 	}
 
+	// Limit the dashboard to a single branch when requested or required by the membership
+	branchID := branchScope(c, member)
+
 	// Get restaurant stats
-	stats, err := h.restaurantService.GetRestaurantStats(c.Request.Context(), restaurant.ID.String())
+	stats, err := h.restaurantService.GetRestaurantStats(c.Request.Context(), restaurant.ID.String(), branchID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	// Get upcoming events
-	upcomingEvents, _, err := h.eventService.GetUpcomingEvents(c.Request.Context(), restaurant.ID.String(), 5, 0)
+	upcomingEvents, _, err := h.eventService.GetUpcomingEvents(c.Request.Context(), restaurant.ID.String(), branchID, 5, 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	// Get today's events
-	todayEvents, err := h.eventService.GetTodayEvents(c.Request.Context(), restaurant.ID.String())
+	todayEvents, err := h.eventService.GetTodayEvents(c.Request.Context(), restaurant.ID.String(), branchID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	// Get pending volunteer applications
 	pendingApps, err := h.volunteerService.GetPendingApplications(c.Request.Context(), restaurant.ID.String())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
+	pendingApps = branchApplications(pendingApps, branchID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"restaurant":      restaurant,
@@ -131,31 +133,32 @@ func (h *RestaurantHandler) GetRestaurant(c *gin.Context) {
 }
 
 func (h *RestaurantHandler) CreateEvent(c *gin.Context) {
-	// Get user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-
-	// Get restaurant by user ID
-	restaurant, err := h.restaurantService.GetRestaurantByUserID(c.Request.Context(), user.(*domain.User).ID.String())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
 		return
 	}
 
 	var event domain.Event
 	if err := c.ShouldBindJSON(&event); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Set restaurant ID
-	event.RestaurantID = restaurant.ID
+	event.RestaurantID = member.RestaurantID
+
+	// Members limited to a branch can only create events for it
+	if member.BranchID != nil {
+		if event.BranchID != nil && *event.BranchID != *member.BranchID {
+			_ = c.Error(permissionDenied("create events for this branch"))
+			return
+		}
+		event.BranchID = member.BranchID
+	}
 
 	if err := h.eventService.CreateEvent(c.Request.Context(), &event); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -167,14 +170,26 @@ func (h *RestaurantHandler) GetEvent(c *gin.Context) {
 
 	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		_ = c.Error(err)
+		return
+	}
+
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if !event.HostCan(member.RestaurantID, domain.PermissionViewEvents) || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("view this event"))
 		return
 	}
 
 	// Get volunteers for this event
 	volunteers, err := h.volunteerService.GetEventVolunteers(c.Request.Context(), eventID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -190,47 +205,53 @@ func (h *RestaurantHandler) UpdateEvent(c *gin.Context) {
 	// Get the existing event
 	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	// Get user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
 		return
 	}
 
-	// Get restaurant by user ID
-	restaurant, err := h.restaurantService.GetRestaurantByUserID(c.Request.Context(), user.(*domain.User).ID.String())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Verify ownership
+	if !event.HostCan(member.RestaurantID, domain.PermissionManageEvents) || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("update this event"))
 		return
 	}
 
-	// Verify ownership
-	if event.RestaurantID != restaurant.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission to update this event"})
+	// Bind the editable fields, everything else stays as loaded
+	var req domain.UpdateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	// Bind updated event data
-	var updatedEvent domain.Event
-	if err := c.ShouldBindJSON(&updatedEvent); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	// Keep the branch unless it is moved to another one the member has access to
+	if req.BranchID != nil {
+		if !member.CanAccessBranch(req.BranchID) {
+			_ = c.Error(permissionDenied("move this event to another branch"))
+			return
+		}
+		event.BranchID = req.BranchID
 	}
 
-	// Preserve the ID and restaurant ID
-	updatedEvent.ID = event.ID
-	updatedEvent.RestaurantID = event.RestaurantID
+	event.Title = req.Title
+	event.Description = req.Description
+	event.Date = req.Date
+	event.StartTime = req.StartTime
+	event.EndTime = req.EndTime
+	event.Location = req.Location
+	event.MaxGuests = req.MaxGuests
+	event.MaxVolunteers = req.MaxVolunteers
 
-	if err := h.eventService.UpdateEvent(c.Request.Context(), &updatedEvent); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.eventService.UpdateEvent(c.Request.Context(), event); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, updatedEvent)
+	c.JSON(http.StatusOK, event)
 }
 
 func (h *RestaurantHandler) DeleteEvent(c *gin.Context) {
@@ -239,32 +260,24 @@ func (h *RestaurantHandler) DeleteEvent(c *gin.Context) {
 	// Get the existing event
 	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	// Get user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-
-	// Get restaurant by user ID
-	restaurant, err := h.restaurantService.GetRestaurantByUserID(c.Request.Context(), user.(*domain.User).ID.String())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
 		return
 	}
 
 	// Verify ownership
-	if event.RestaurantID != restaurant.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission to delete this event"})
+	if event.RestaurantID != member.RestaurantID || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("delete this event"))
 		return
 	}
 
 	if err := h.eventService.DeleteEvent(c.Request.Context(), eventID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -277,27 +290,19 @@ func (h *RestaurantHandler) UpdateEventStatus(c *gin.Context) {
 	// Get the existing event
 	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	// Get user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-
-	// Get restaurant by user ID
-	restaurant, err := h.restaurantService.GetRestaurantByUserID(c.Request.Context(), user.(*domain.User).ID.String())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
 		return
 	}
 
 	// Verify ownership
-	if event.RestaurantID != restaurant.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission to update this event"})
+	if !event.HostCan(member.RestaurantID, domain.PermissionManageEvents) || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("update this event"))
 		return
 	}
 
@@ -306,12 +311,12 @@ func (h *RestaurantHandler) UpdateEventStatus(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	if err := h.eventService.UpdateEventStatus(c.Request.Context(), eventID, req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -324,27 +329,19 @@ func (h *RestaurantHandler) UpdateGuestCount(c *gin.Context) {
 	// Get the existing event
 	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	// Get user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-
-	// Get restaurant by user ID
-	restaurant, err := h.restaurantService.GetRestaurantByUserID(c.Request.Context(), user.(*domain.User).ID.String())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
 		return
 	}
 
 	// Verify ownership
-	if event.RestaurantID != restaurant.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission to update this event"})
+	if !event.HostCan(member.RestaurantID, domain.PermissionManageEvents) || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("update this event"))
 		return
 	}
 
@@ -353,12 +350,12 @@ func (h *RestaurantHandler) UpdateGuestCount(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	if err := h.eventService.UpdateGuestCount(c.Request.Context(), eventID, req.Count); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -371,27 +368,19 @@ func (h *RestaurantHandler) UpdateMealsServed(c *gin.Context) {
 	// Get the existing event
 	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
-		return
-	}
-
-	// Get user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		_ = c.Error(err)
 		return
 	}
 
-	// Get restaurant by user ID
-	restaurant, err := h.restaurantService.GetRestaurantByUserID(c.Request.Context(), user.(*domain.User).ID.String())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
 		return
 	}
 
 	// Verify ownership
-	if event.RestaurantID != restaurant.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission to update this event"})
+	if !event.HostCan(member.RestaurantID, domain.PermissionRecordMeals) || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("update this event"))
 		return
 	}
 
@@ -400,12 +389,12 @@ func (h *RestaurantHandler) UpdateMealsServed(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	if err := h.eventService.UpdateMealsServed(c.Request.Context(), eventID, req.Count); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.eventService.UpdateMealsServed(c.Request.Context(), eventID, member.RestaurantID.String(), c.GetString("user_id"), req.Count); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -413,34 +402,30 @@ func (h *RestaurantHandler) UpdateMealsServed(c *gin.Context) {
 }
 
 func (h *RestaurantHandler) GetVolunteerApplications(c *gin.Context) {
-	// Get user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
-	}
-
-	// Get restaurant by user ID
-	restaurant, err := h.restaurantService.GetRestaurantByUserID(c.Request.Context(), user.(*domain.User).ID.String())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
 		return
 	}
 
-	applications, err := h.volunteerService.GetPendingApplications(c.Request.Context(), restaurant.ID.String())
+	applications, err := h.volunteerService.GetPendingApplications(c.Request.Context(), member.RestaurantID.String())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
+	applications = branchApplications(applications, branchScope(c, member))
 
 	c.JSON(http.StatusOK, applications)
 }
 
 func (h *RestaurantHandler) ApproveVolunteerApplication(c *gin.Context) {
-	applicationID := c.Param("id")
+	application, ok := h.ownApplication(c)
+	if !ok {
+		return
+	}
 
-	if err := h.volunteerService.ApproveApplication(c.Request.Context(), applicationID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.volunteerService.ApproveApplication(c.Request.Context(), application.ID.String()); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -448,12 +433,342 @@ func (h *RestaurantHandler) ApproveVolunteerApplication(c *gin.Context) {
 }
 
 func (h *RestaurantHandler) DeclineVolunteerApplication(c *gin.Context) {
-	applicationID := c.Param("id")
+	application, ok := h.ownApplication(c)
+	if !ok {
+		return
+	}
 
-	if err := h.volunteerService.DeclineApplication(c.Request.Context(), applicationID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.volunteerService.DeclineApplication(c.Request.Context(), application.ID.String()); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "application declined successfully"})
 }
+
+func (h *RestaurantHandler) GetEventHosts(c *gin.Context) {
+	event, ok := h.ownEvent(c)
+	if !ok {
+		return
+	}
+
+	hosts, err := h.eventService.GetHosts(c.Request.Context(), event.ID.String())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hosts)
+}
+
+func (h *RestaurantHandler) AddEventHost(c *gin.Context) {
+	event, ok := h.ownEvent(c)
+	if !ok {
+		return
+	}
+
+	var req domain.AddEventHostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	host := &domain.EventHost{
+		EventID:               event.ID,
+		RestaurantID:          req.RestaurantID,
+		CanEditDetails:        req.CanEditDetails,
+		CanReviewApplications: req.CanReviewApplications,
+		CanRecordMeals:        req.CanRecordMeals,
+	}
+
+	if err := h.eventService.AddHost(c.Request.Context(), host); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, host)
+}
+
+func (h *RestaurantHandler) UpdateEventHost(c *gin.Context) {
+	host, ok := h.ownEventHost(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateEventHostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	host.CanEditDetails = req.CanEditDetails
+	host.CanReviewApplications = req.CanReviewApplications
+	host.CanRecordMeals = req.CanRecordMeals
+
+	if err := h.eventService.UpdateHost(c.Request.Context(), host); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, host)
+}
+
+func (h *RestaurantHandler) RemoveEventHost(c *gin.Context) {
+	host, ok := h.ownEventHost(c)
+	if !ok {
+		return
+	}
+
+	if err := h.eventService.RemoveHost(c.Request.Context(), host.ID.String()); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "host removed successfully"})
+}
+
+func (h *RestaurantHandler) RecordMeals(c *gin.Context) {
+	eventID := c.Param("id")
+
+	// Get the existing event
+	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if !event.HostCan(member.RestaurantID, domain.PermissionRecordMeals) || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("update this event"))
+		return
+	}
+
+	var req struct {
+		Count int    `json:"count" binding:"required,gt=0"`
+		Note  string `json:"note" binding:"max=255"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	entry, err := h.eventService.RecordMeals(c.Request.Context(), eventID, member.RestaurantID.String(), c.GetString("user_id"), req.Count, req.Note)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+func (h *RestaurantHandler) GetMealLog(c *gin.Context) {
+	eventID := c.Param("id")
+
+	// Get the existing event
+	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if !event.HostCan(member.RestaurantID, domain.PermissionRecordMeals) || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("view this event"))
+		return
+	}
+
+	entries, err := h.eventService.GetMealLog(c.Request.Context(), eventID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"meals_served": event.MealsServed,
+		"entries":      entries,
+	})
+}
+
+func (h *RestaurantHandler) CorrectMealEntry(c *gin.Context) {
+	eventID := c.Param("id")
+	entryID := c.Param("entry_id")
+
+	// Get the existing event
+	event, err := h.eventService.GetEventByID(c.Request.Context(), eventID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Get the membership the user acts through
+	member, ok := currentMembership(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if !event.HostCan(member.RestaurantID, domain.PermissionRecordMeals) || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("update this event"))
+		return
+	}
+
+	var req struct {
+		Note string `json:"note" binding:"required,max=255"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	entry, err := h.eventService.CorrectMealEntry(c.Request.Context(), eventID, entryID, member.RestaurantID.String(), c.GetString("user_id"), req.Note)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// ownEvent loads the event addressed by the request and checks the user acts for the
+// restaurant that created it, only that restaurant manages the co-hosts
+func (h *RestaurantHandler) ownEvent(c *gin.Context) (*domain.Event, bool) {
+	event, err := h.eventService.GetEventByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	member, ok := currentMembership(c)
+	if !ok {
+		return nil, false
+	}
+
+	// Verify ownership
+	if event.RestaurantID != member.RestaurantID || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("manage the hosts of this event"))
+		return nil, false
+	}
+
+	return event, true
+}
+
+// ownEventHost loads the co-host addressed by the request and checks it belongs to an
+// event of the restaurant the user acts for
+func (h *RestaurantHandler) ownEventHost(c *gin.Context) (*domain.EventHost, bool) {
+	event, ok := h.ownEvent(c)
+	if !ok {
+		return nil, false
+	}
+
+	host, err := h.eventService.GetHost(c.Request.Context(), c.Param("host_id"))
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+	if host.EventID != event.ID {
+		_ = c.Error(domain.ErrEventHostNotFound)
+		return nil, false
+	}
+
+	return host, true
+}
+
+// ownApplication loads the application addressed by the request and checks the user acts
+// for a host allowed to review the applications of its event
+func (h *RestaurantHandler) ownApplication(c *gin.Context) (*domain.VolunteerApplication, bool) {
+	application, err := h.volunteerService.GetApplication(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	event, err := h.eventService.GetEventByID(c.Request.Context(), application.EventID.String())
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	member, ok := currentMembership(c)
+	if !ok {
+		return nil, false
+	}
+
+	// Verify ownership
+	if !event.HostCan(member.RestaurantID, domain.PermissionReviewApplications) || !member.CanAccessBranch(event.BranchID) {
+		_ = c.Error(permissionDenied("review this application"))
+		return nil, false
+	}
+
+	return application, true
+}
+
+// currentRestaurant returns the restaurant the request acts for, as resolved from the
+// user's membership by the middleware. It writes the error response itself when missing.
+func currentRestaurant(c *gin.Context) (*domain.Restaurant, bool) {
+	value, exists := c.Get("restaurant")
+	restaurant, ok := value.(*domain.Restaurant)
+	if !exists || !ok || restaurant == nil {
+		_ = c.Error(domain.ErrNoMembership)
+		return nil, false
+	}
+
+	return restaurant, true
+}
+
+// currentMembership returns the membership the request acts through, as resolved by the
+// middleware. It writes the error response itself when missing.
+func currentMembership(c *gin.Context) (*domain.RestaurantMember, bool) {
+	value, exists := c.Get("membership")
+	member, ok := value.(*domain.RestaurantMember)
+	if !exists || !ok || member == nil {
+		_ = c.Error(domain.ErrNoMembership)
+		return nil, false
+	}
+
+	return member, true
+}
+
+// permissionDenied is the error for an action on a record the restaurant may see but
+// not change
+func permissionDenied(action string) error {
+	return domain.NewForbiddenError(domain.ErrCodePermissionDenied, "you don't have permission to "+action)
+}
+
+// branchScope returns the branch a request is limited to. Members tied to a branch always
+// see their own, others may pick one with the branch_id query parameter.
+func branchScope(c *gin.Context, member *domain.RestaurantMember) string {
+	if member.BranchID != nil {
+		return member.BranchID.String()
+	}
+	return c.Query("branch_id")
+}
+
+// branchApplications keeps the applications for events of the branch, all of them when
+// no branch is given
+func branchApplications(apps []*domain.VolunteerApplication, branchID string) []*domain.VolunteerApplication {
+	if branchID == "" {
+		return apps
+	}
+
+	filtered := make([]*domain.VolunteerApplication, 0, len(apps))
+	for _, app := range apps {
+		if app.Event.BranchID != nil && app.Event.BranchID.String() == branchID {
+			filtered = append(filtered, app)
+		}
+	}
+	return filtered
+}
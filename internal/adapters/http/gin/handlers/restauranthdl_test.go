@@ -0,0 +1,83 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin/handlers"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedEventService serves one event and keeps what UpdateEvent is given
+type storedEventService struct {
+	ports.EventService
+	event   domain.Event
+	updated *domain.Event
+}
+
+func (s *storedEventService) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
+	event := s.event
+	return &event, nil
+}
+
+func (s *storedEventService) UpdateEvent(ctx context.Context, event *domain.Event) error {
+	s.updated = event
+	return nil
+}
+
+func TestUpdateEventKeepsServerFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	createdAt := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	events := &storedEventService{event: domain.Event{
+		ID:            uuid.New(),
+		RestaurantID:  uuid.New(),
+		Title:         "Iftar",
+		Status:        domain.EventStatusUpcoming,
+		CurrentGuests: 12,
+		MealsServed:   40,
+		CreatedAt:     createdAt,
+	}}
+	handler := handlers.NewRestaurantHandler(nil, events, nil)
+
+	engine := gin.New()
+	engine.PUT("/events/:id", func(c *gin.Context) {
+		c.Set("membership", &domain.RestaurantMember{RestaurantID: events.event.RestaurantID, Role: domain.MembershipRoleOwner})
+		handler.UpdateEvent(c)
+	})
+
+	body := `{
+		"title": "Iftar for the neighbourhood",
+		"date": "2026-11-01T00:00:00Z",
+		"start_time": "2026-11-01T17:00:00Z",
+		"end_time": "2026-11-01T20:00:00Z",
+		"max_guests": 80,
+		"status": "canceled",
+		"current_guests": 0,
+		"meals_served": 1000,
+		"created_at": "2020-01-01T00:00:00Z"
+	}`
+	req := httptest.NewRequest(http.MethodPut, "/events/"+events.event.ID.String(), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, events.updated)
+
+	assert.Equal(t, "Iftar for the neighbourhood", events.updated.Title)
+	assert.Equal(t, 80, events.updated.MaxGuests)
+	assert.Equal(t, domain.EventStatusUpcoming, events.updated.Status)
+	assert.Equal(t, 12, events.updated.CurrentGuests)
+	assert.Equal(t, 40, events.updated.MealsServed)
+	assert.Equal(t, createdAt, events.updated.CreatedAt)
+}
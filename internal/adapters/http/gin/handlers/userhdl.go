@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
@@ -21,7 +26,7 @@ func NewUserHandler(userService ports.UserService) *UserHandler {
 func (h *UserHandler) GetMe(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		_ = c.Error(domain.ErrAuthenticationRequired)
 		return
 	}
 
@@ -31,30 +36,98 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 func (h *UserHandler) UpdateMe(c *gin.Context) {
 	currentUser, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		_ = c.Error(domain.ErrAuthenticationRequired)
 		return
 	}
 
 	user, ok := currentUser.(*domain.User)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user type"})
+		_ = c.Error(domain.ErrInvalidUserType)
 		return
 	}
 
 	var updatedUser domain.User
 	if err := c.ShouldBindJSON(&updatedUser); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	// Update only allowed fields
+	// Update only allowed fields, the email changes through ChangeEmail
 	user.Username = updatedUser.Username
-	user.Email = updatedUser.Email
 
 	if err := h.userService.UpdateUser(c.Request.Context(), user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, user)
 }
+
+// ExportData returns everything stored about the current user, as JSON or, with
+// ?format=zip, as a ZIP archive holding the same JSON document
+func (h *UserHandler) ExportData(c *gin.Context) {
+	export, err := h.userService.ExportUserData(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if c.Query("format") != "zip" {
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	file, err := writer.CreateHeader(&zip.FileHeader{Name: "data.json", Method: zip.Deflate, Modified: export.ExportedAt})
+	if err == nil {
+		_, err = file.Write(data)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filename := fmt.Sprintf("dcf-data-%s.zip", export.ExportedAt.UTC().Format(time.DateOnly))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
+
+// RequestDeletion schedules the deletion of the current user's account, it can be
+// cancelled until deletion_due_at
+func (h *UserHandler) RequestDeletion(c *gin.Context) {
+	var req domain.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	user, err := h.userService.RequestAccountDeletion(c.Request.Context(), c.GetString("user_id"), c.GetString("session_id"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Account deletion scheduled successfully",
+		"deletion_due_at": user.DeletionDueAt,
+	})
+}
+
+func (h *UserHandler) CancelDeletion(c *gin.Context) {
+	if _, err := h.userService.CancelAccountDeletion(c.Request.Context(), c.GetString("user_id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deletion cancelled successfully"})
+}
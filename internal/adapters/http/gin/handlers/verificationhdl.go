@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type VerificationHandler struct {
+	verificationService ports.VerificationService
+	maxUploadSize       int64
+}
+
+func NewVerificationHandler(
+	verificationService ports.VerificationService,
+	cfg *config.Config,
+) *VerificationHandler {
+	return &VerificationHandler{
+		verificationService: verificationService,
+		maxUploadSize:       cfg.Storage.MaxUploadSize,
+	}
+}
+
+func (h *VerificationHandler) GetVerification(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	documents, err := h.verificationService.GetDocuments(c.Request.Context(), restaurant.ID.String())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":           restaurant.VerificationStatus,
+		"verified_at":      restaurant.VerifiedAt,
+		"rejection_reason": restaurant.RejectionReason,
+		"documents":        documents,
+	})
+}
+
+func (h *VerificationHandler) UploadDocument(c *gin.Context) {
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize)
+
+	var req domain.UploadDocumentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		_ = c.Error(domain.ErrFileRequired)
+		return
+	}
+
+	if fileHeader.Size > h.maxUploadSize {
+		_ = c.Error(domain.ErrFileTooLarge)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	document, err := h.verificationService.UploadDocument(c.Request.Context(), restaurant.ID.String(), req.DocumentType, fileHeader.Filename, file)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+func (h *VerificationHandler) DeleteDocument(c *gin.Context) {
+	document, err := h.verificationService.GetDocument(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	restaurant, ok := currentRestaurant(c)
+	if !ok {
+		return
+	}
+
+	// Verify ownership
+	if document.RestaurantID != restaurant.ID {
+		_ = c.Error(permissionDenied("delete this document"))
+		return
+	}
+
+	if err := h.verificationService.DeleteDocument(c.Request.Context(), document.ID.String()); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "document deleted successfully"})
+}
+
+func (h *VerificationHandler) GetRestaurantDocuments(c *gin.Context) {
+	documents, err := h.verificationService.GetDocuments(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+func (h *VerificationHandler) DownloadDocument(c *gin.Context) {
+	document, err := h.verificationService.GetDocument(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	content, err := h.verificationService.OpenDocument(c.Request.Context(), document)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer content.Close()
+
+	c.Header("Content-Disposition", "attachment; filename="+strconv.Quote(document.FileName))
+	c.DataFromReader(http.StatusOK, document.Size, document.ContentType, content, nil)
+}
+
+func (h *VerificationHandler) ApproveRestaurant(c *gin.Context) {
+	if err := h.verificationService.ApproveRestaurant(c.Request.Context(), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "restaurant verified successfully"})
+}
+
+func (h *VerificationHandler) RejectRestaurant(c *gin.Context) {
+	var req domain.RejectRestaurantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.verificationService.RejectRestaurant(c.Request.Context(), c.Param("id"), req.Reason); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "restaurant rejected successfully"})
+}
@@ -22,13 +22,13 @@ func (h *VolunteerHandler) GetVolunteerDashboard(c *gin.Context) {
 
 	volunteer, err := h.volunteerService.GetVolunteerByUserID(c, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Volunteer not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	dashboard, err := h.volunteerService.GetVolunteerDashboard(c, volunteer.ID.String())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -40,13 +40,13 @@ func (h *VolunteerHandler) GetUpcomingTasks(c *gin.Context) {
 
 	volunteer, err := h.volunteerService.GetVolunteerByUserID(c, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Volunteer not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	tasks, err := h.volunteerService.GetUpcomingTasks(c, volunteer.ID.String())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -58,13 +58,13 @@ func (h *VolunteerHandler) GetNearbyOpportunities(c *gin.Context) {
 
 	volunteer, err := h.volunteerService.GetVolunteerByUserID(c, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Volunteer not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	opportunities, err := h.volunteerService.GetNearbyOpportunities(c, volunteer.ID.String())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -76,13 +76,13 @@ func (h *VolunteerHandler) GetVolunteerBadges(c *gin.Context) {
 
 	volunteer, err := h.volunteerService.GetVolunteerByUserID(c, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Volunteer not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	badges, err := h.volunteerService.GetVolunteerBadges(c, volunteer.ID.String())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -94,7 +94,7 @@ func (h *VolunteerHandler) ApplyForEvent(c *gin.Context) {
 
 	volunteer, err := h.volunteerService.GetVolunteerByUserID(c, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Volunteer not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -104,13 +104,13 @@ func (h *VolunteerHandler) ApplyForEvent(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	err = h.volunteerService.ApplyForEvent(c, volunteer.ID.String(), req.EventID, req.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -123,13 +123,13 @@ func (h *VolunteerHandler) CheckInForEvent(c *gin.Context) {
 
 	volunteer, err := h.volunteerService.GetVolunteerByUserID(c, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Volunteer not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	err = h.volunteerService.CheckInForEvent(c, volunteer.ID.String(), eventVolunteerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
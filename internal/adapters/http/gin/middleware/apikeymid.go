@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries the API keys a restaurant's own tools authenticate with
+const APIKeyHeader = "X-API-Key"
+
+type APIKeyMiddleware struct {
+	apiKeyService ports.APIKeyService
+}
+
+func NewAPIKeyMiddleware(apiKeyService ports.APIKeyService) *APIKeyMiddleware {
+	return &APIKeyMiddleware{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Authenticate accepts an API key in place of a user session. It stores what Authenticate
+// and ResolveRestaurant would for the member who created the key, and those let the request
+// through unchanged. It must run before them.
+func (m *APIKeyMiddleware) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(APIKeyHeader)
+		if rawKey == "" {
+			c.Next()
+			return
+		}
+
+		key, user, member, err := m.apiKeyService.AuthenticateAPIKey(c.Request.Context(), rawKey)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Set("api_key", key)
+		c.Set("user", user)
+		c.Set("user_id", user.ID.String())
+		c.Set("role", user.Type)
+		c.Set("membership", member)
+		c.Set("restaurant", member.Restaurant)
+		updateAuditContext(c, func(auditCtx *domain.AuditContext) {
+			auditCtx.ActorID = &user.ID
+			auditCtx.APIKeyID = &key.ID
+		})
+		c.Next()
+	}
+}
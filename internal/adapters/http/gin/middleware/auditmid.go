@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditContextMiddleware stores how the request was made in its context, the services
+// copy it onto the audit entries they write. Authenticate and APIKeyMiddleware add who made it.
+func AuditContextMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := domain.WithAuditContext(c.Request.Context(), domain.AuditContext{
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// updateAuditContext applies update to the audit context of the request
+func updateAuditContext(c *gin.Context, update func(auditCtx *domain.AuditContext)) {
+	auditCtx := domain.AuditContextFrom(c.Request.Context())
+	update(&auditCtx)
+	c.Request = c.Request.WithContext(domain.WithAuditContext(c.Request.Context(), auditCtx))
+}
@@ -1,39 +1,277 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// CSRFCookie and CSRFHeader carry the double-submit token that cookie-authenticated
+	// requests changing state must present twice
+	CSRFCookie = "csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+
+	// RefreshCookie carries the refresh token of browsers, only to the refresh and logout
+	// endpoints
+	RefreshCookie = "refresh_token"
+
+	// ImpersonatedByHeader flags the responses to impersonated sessions with the ID of the
+	// administrator behind them
+	ImpersonatedByHeader = "X-Impersonated-By"
+)
+
 type AuthMiddleware struct {
-	authService ports.AuthService
+	authService      ports.AuthService
+	twoFactorService ports.TwoFactorService
+	auditService     ports.AuditService
 }
 
-func NewAuthMiddleware(authService ports.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService ports.AuthService, twoFactorService ports.TwoFactorService, auditService ports.AuditService) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
+		authService:      authService,
+		twoFactorService: twoFactorService,
+		auditService:     auditService,
 	}
 }
 
+// Authenticate accepts an access token from the Authorization header, as sent by mobile
+// and API clients, or from the auth_token cookie set for browsers
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token, err := c.Cookie("auth_token")
-		if err != nil {
-			c.JSON(401, gin.H{"error": "authentication required"})
-			c.Abort()
+		// Authenticated by APIKeyMiddleware already
+		if _, ok := c.Get("api_key"); ok {
+			c.Next()
 			return
 		}
 
-		user, profile, err := m.authService.ValidateToken(c.Request.Context(), token)
+		token, fromCookie := requestToken(c)
+		if token == "" {
+			abortWithError(c, domain.ErrAuthenticationRequired)
+			return
+		}
+
+		// Browsers send cookies along with cross-site requests, bearer tokens they do not
+		if fromCookie && !validCSRFToken(c) {
+			abortWithError(c, domain.ErrInvalidCSRFToken)
+			return
+		}
+
+		user, profile, session, err := m.authService.ValidateToken(c.Request.Context(), token)
 		if err != nil {
-			c.SetCookie("auth_token", "", -1, "/", "", false, true)
-			c.JSON(401, gin.H{"error": "invalid or expired session"})
-			c.Abort()
+			if fromCookie {
+				c.SetCookie("auth_token", "", -1, "/", "", false, true)
+			}
+			abortWithError(c, domain.ErrInvalidSession)
 			return
 		}
 
 		c.Set("user", user)
+		c.Set("user_id", user.ID.String())
+		c.Set("session_id", session.ID.String())
+		c.Set("role", user.Type)
 		c.Set("profile", profile)
+		updateAuditContext(c, func(auditCtx *domain.AuditContext) {
+			auditCtx.ActorID = &user.ID
+			auditCtx.ImpersonatorID = session.ImpersonatorID
+		})
+
+		if !session.IsImpersonated() {
+			c.Next()
+			return
+		}
+
+		// Every request made as the user is recorded along with who made it
+		c.Set("impersonator_id", session.ImpersonatorID.String())
+		c.Header(ImpersonatedByHeader, session.ImpersonatorID.String())
+		c.Next()
+
+		err = m.auditService.Record(c.Request.Context(), &domain.AuditLog{
+			ActorID:        &user.ID,
+			ImpersonatorID: session.ImpersonatorID,
+			Action:         domain.AuditActionImpersonatedRequest,
+			Method:         c.Request.Method,
+			Path:           c.Request.URL.Path,
+			Status:         responseStatus(c),
+			IPAddress:      c.ClientIP(),
+			UserAgent:      c.Request.UserAgent(),
+		})
+		if err != nil {
+			log.Printf("Failed to record impersonated request %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		}
+	}
+}
+
+// ForbidImpersonation stops impersonated sessions from changing how the account is
+// secured and from what only the user should decide. It must run after Authenticate.
+func (m *AuthMiddleware) ForbidImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("impersonator_id"); ok {
+			abortWithError(c, domain.ErrForbiddenImpersonating)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole only lets through users whose role is one of the given roles.
+// It must run after Authenticate.
+func (m *AuthMiddleware) RequireRole(roles ...domain.UserType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := currentRole(c)
+		if !ok {
+			abortWithError(c, domain.ErrAuthenticationRequired)
+			return
+		}
+
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+
+		forbidden(c)
+	}
+}
+
+// RequirePermission only lets through users whose role grants all the given permissions.
+// It must run after Authenticate.
+func (m *AuthMiddleware) RequirePermission(permissions ...domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := currentRole(c)
+		if !ok {
+			abortWithError(c, domain.ErrAuthenticationRequired)
+			return
+		}
+
+		for _, p := range permissions {
+			if !role.Can(p) {
+				forbidden(c)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireVerifiedEmail only lets through users who verified their email address.
+// It must run after Authenticate.
+func (m *AuthMiddleware) RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get("user")
+		user, ok := value.(*domain.User)
+		if !ok {
+			abortWithError(c, domain.ErrAuthenticationRequired)
+			return
+		}
+
+		if !user.IsEmailVerified() {
+			abortWithError(c, domain.ErrEmailVerificationNeeded)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireTwoFactorEnrollment stops organization owners who have not enrolled in two-factor
+// authentication while the security policy requires it. It must run after ResolveRestaurant.
+func (m *AuthMiddleware) RequireTwoFactorEnrollment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get("user")
+		user, ok := value.(*domain.User)
+		if !ok {
+			abortWithError(c, domain.ErrAuthenticationRequired)
+			return
+		}
+
+		// Only owners are covered by the policy, which saves a lookup for everyone else
+		value, _ = c.Get("membership")
+		member, ok := value.(*domain.RestaurantMember)
+		if user.IsTwoFactorEnabled() || !ok || member.Role != domain.MembershipRoleOwner {
+			c.Next()
+			return
+		}
+
+		required, err := m.twoFactorService.TwoFactorEnrollmentRequired(c.Request.Context(), user.ID.String())
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if required {
+			abortWithError(c, domain.ErrTwoFactorEnrollment)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requestToken returns the access token of the request and whether it came from a cookie
+func requestToken(c *gin.Context) (string, bool) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found {
+			return "", false
+		}
+		return strings.TrimSpace(token), false
+	}
+
+	token, err := c.Cookie("auth_token")
+	if err != nil {
+		return "", false
+	}
+	return token, true
+}
+
+// RequireRefreshCSRF checks the double-submit token of requests that carry the refresh token
+// as a cookie, a cross-site request would otherwise rotate or end the session. Clients that
+// send the refresh token in the body need no token.
+func RequireRefreshCSRF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := c.Cookie(RefreshCookie); err == nil && !validCSRFToken(c) {
+			abortWithError(c, domain.ErrInvalidCSRFToken)
+			return
+		}
+
 		c.Next()
 	}
 }
+
+// validCSRFToken checks the double-submit token of requests that change state
+func validCSRFToken(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	cookie, err := c.Cookie(CSRFCookie)
+	header := c.GetHeader(CSRFHeader)
+	if err != nil || cookie == "" || header == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
+
+func currentRole(c *gin.Context) (domain.UserType, bool) {
+	value, exists := c.Get("role")
+	if !exists {
+		return "", false
+	}
+
+	role, ok := value.(domain.UserType)
+	return role, ok
+}
+
+func forbidden(c *gin.Context) {
+	abortWithError(c, domain.ErrPermissionDenied)
+}
@@ -1 +1,146 @@
 package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin/middleware"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validToken = "valid-token"
+
+// tokenAuthService accepts validToken only, other methods are not used by Authenticate
+type tokenAuthService struct {
+	ports.AuthService
+	validated int
+}
+
+func (s *tokenAuthService) ValidateToken(ctx context.Context, token string) (*domain.User, interface{}, *domain.Session, error) {
+	s.validated++
+	if token != validToken {
+		return nil, nil, nil, domain.ErrInvalidToken
+	}
+	user := &domain.User{ID: uuid.New(), Type: domain.UserTypeVolunteer}
+	return user, nil, &domain.Session{ID: uuid.New(), UserID: user.ID}, nil
+}
+
+func TestAuthenticateCSRF(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		authCookie    string
+		csrfCookie    string
+		csrfHeader    string
+		wantStatus    int
+		wantCode      string
+	}{
+		{"cookie read without token", http.MethodGet, "", validToken, "", "", http.StatusOK, ""},
+		{"cookie head without token", http.MethodHead, "", validToken, "", "", http.StatusOK, ""},
+		{"cookie options without token", http.MethodOptions, "", validToken, "", "", http.StatusOK, ""},
+		{"cookie write with matching token", http.MethodPost, "", validToken, "abc123", "abc123", http.StatusOK, ""},
+		{"cookie write without token", http.MethodPost, "", validToken, "", "", http.StatusForbidden, "invalid_csrf_token"},
+		{"cookie write without header", http.MethodPut, "", validToken, "abc123", "", http.StatusForbidden, "invalid_csrf_token"},
+		{"cookie write without cookie", http.MethodPatch, "", validToken, "", "abc123", http.StatusForbidden, "invalid_csrf_token"},
+		{"cookie write with other token", http.MethodDelete, "", validToken, "abc123", "abc124", http.StatusForbidden, "invalid_csrf_token"},
+		{"bearer write without token", http.MethodPost, "Bearer " + validToken, "", "", "", http.StatusOK, ""},
+		{"bearer wins over cookie", http.MethodPost, "Bearer " + validToken, validToken, "", "", http.StatusOK, ""},
+		{"no credentials", http.MethodGet, "", "", "", "", http.StatusUnauthorized, "authentication_required"},
+		{"not a bearer token", http.MethodGet, "Basic dXNlcjpwYXNz", "", "", "", http.StatusUnauthorized, "authentication_required"},
+		{"invalid token", http.MethodGet, "Bearer expired", "", "", "", http.StatusUnauthorized, "invalid_session"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService := &tokenAuthService{}
+			authMiddleware := middleware.NewAuthMiddleware(authService, nil, nil)
+
+			router := gin.New()
+			router.Use(middleware.ErrorHandler())
+			router.Any("/", authMiddleware.Authenticate(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.authCookie != "" {
+				req.AddCookie(&http.Cookie{Name: "auth_token", Value: tt.authCookie})
+			}
+			if tt.csrfCookie != "" {
+				req.AddCookie(&http.Cookie{Name: middleware.CSRFCookie, Value: tt.csrfCookie})
+			}
+			if tt.csrfHeader != "" {
+				req.Header.Set(middleware.CSRFHeader, tt.csrfHeader)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantCode == "" {
+				return
+			}
+
+			var problem middleware.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.wantCode, problem.Code)
+
+			// The CSRF check comes before the session is looked up
+			if tt.wantCode == "invalid_csrf_token" {
+				assert.Zero(t, authService.validated)
+			}
+		})
+	}
+}
+
+func TestRequireRefreshCSRF(t *testing.T) {
+	tests := []struct {
+		name          string
+		refreshCookie string
+		csrfCookie    string
+		csrfHeader    string
+		wantStatus    int
+	}{
+		{"cookie with matching token", "session.secret", "abc123", "abc123", http.StatusOK},
+		{"cookie without token", "session.secret", "", "", http.StatusForbidden},
+		{"cookie without header", "session.secret", "abc123", "", http.StatusForbidden},
+		{"cookie with other token", "session.secret", "abc123", "abc124", http.StatusForbidden},
+		{"token in the body", "", "", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.ErrorHandler())
+			router.POST("/", middleware.RequireRefreshCSRF(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.refreshCookie != "" {
+				req.AddCookie(&http.Cookie{Name: middleware.RefreshCookie, Value: tt.refreshCookie})
+			}
+			if tt.csrfCookie != "" {
+				req.AddCookie(&http.Cookie{Name: middleware.CSRFCookie, Value: tt.csrfCookie})
+			}
+			if tt.csrfHeader != "" {
+				req.Header.Set(middleware.CSRFHeader, tt.csrfHeader)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of the error responses, see RFC 7807
+const ProblemContentType = "application/problem+json"
+
+const internalErrorMessage = "an unexpected error occurred, please try again later"
+
+// Problem is an RFC 7807 problem detail. Code is the stable code of the domain error,
+// clients branch on it rather than on the human-readable detail.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Code     string `json:"code"`
+	Instance string `json:"instance,omitempty"`
+}
+
+var errorKindStatus = map[domain.ErrorKind]int{
+	domain.ErrorKindNotFound:     http.StatusNotFound,
+	domain.ErrorKindConflict:     http.StatusConflict,
+	domain.ErrorKindValidation:   http.StatusBadRequest,
+	domain.ErrorKindForbidden:    http.StatusForbidden,
+	domain.ErrorKindUnauthorized: http.StatusUnauthorized,
+	domain.ErrorKindRateLimited:  http.StatusTooManyRequests,
+	domain.ErrorKindInternal:     http.StatusInternalServerError,
+}
+
+// ErrorHandler renders the last error a handler or middleware added with c.Error, unless
+// a response was written already. Handlers add the errors of binding the request with
+// gin.ErrorTypeBind. It must run before every other middleware that reports errors.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		err := c.Errors.Last()
+		if err == nil || c.Writer.Written() {
+			return
+		}
+
+		problem := newProblem(err)
+		problem.Instance = c.Request.URL.Path
+		if problem.Status == http.StatusInternalServerError {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err.Err)
+		}
+
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// ErrorMessage is the text of err that can be shown to a client, for the responses that
+// cannot carry a problem detail such as redirects
+func ErrorMessage(err error) string {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	return internalErrorMessage
+}
+
+func newProblem(err *gin.Error) Problem {
+	if err.IsType(gin.ErrorTypeBind) {
+		return Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusBadRequest),
+			Status: http.StatusBadRequest,
+			Detail: err.Error(),
+			Code:   domain.ErrCodeInvalidRequest,
+		}
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err.Err, &domainErr) {
+		status, ok := errorKindStatus[domainErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		return Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(status),
+			Status: status,
+			Detail: domainErr.Message,
+			Code:   domainErr.Code,
+		}
+	}
+
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+		Detail: internalErrorMessage,
+		Code:   "internal_error",
+	}
+}
+
+// responseStatus is the status the request is answered with, including the error
+// ErrorHandler has yet to render
+func responseStatus(c *gin.Context) int {
+	if err := c.Errors.Last(); err != nil && !c.Writer.Written() {
+		return newProblem(err).Status
+	}
+	return c.Writer.Status()
+}
+
+// abortWithError stops the chain with err, which ErrorHandler renders
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
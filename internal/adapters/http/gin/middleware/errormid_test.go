@@ -0,0 +1,131 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin/middleware"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		bind       bool
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{"not found", domain.ErrEventNotFound, false, http.StatusNotFound, "event_not_found", domain.ErrEventNotFound.Message},
+		{"conflict", domain.ErrEmailTaken, false, http.StatusConflict, "email_taken", domain.ErrEmailTaken.Message},
+		{"validation", domain.ErrInvalidMealCount, false, http.StatusBadRequest, "invalid_meal_count", domain.ErrInvalidMealCount.Message},
+		{"forbidden", domain.ErrPermissionDenied, false, http.StatusForbidden, domain.ErrCodePermissionDenied, domain.ErrPermissionDenied.Message},
+		{"unauthorized", domain.ErrInvalidToken, false, http.StatusUnauthorized, "invalid_token", domain.ErrInvalidToken.Message},
+		{"rate limited", domain.ErrTooManyFailedSignIns, false, http.StatusTooManyRequests, domain.ErrCodeTooManyAttempts, domain.ErrTooManyFailedSignIns.Message},
+		{"internal kind", domain.ErrInvalidUserType, false, http.StatusInternalServerError, "invalid_user_type", domain.ErrInvalidUserType.Message},
+		{"wrapped", fmt.Errorf("loading event: %w", domain.ErrEventNotFound), false, http.StatusNotFound, "event_not_found", domain.ErrEventNotFound.Message},
+		{"message written for the occasion", domain.NewConflictError(domain.ErrCodeInsufficientStock, "only 2.00 kg of rice left in stock"), false, http.StatusConflict, domain.ErrCodeInsufficientStock, "only 2.00 kg of rice left in stock"},
+		{"binding", errors.New("Key: 'Count' Error:Field validation for 'Count' failed on the 'required' tag"), true, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "Key: 'Count' Error:Field validation for 'Count' failed on the 'required' tag"},
+		{"internal error text stays hidden", errors.New("pq: connection refused"), false, http.StatusInternalServerError, "internal_error", "an unexpected error occurred, please try again later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.ErrorHandler())
+			router.GET("/events/:id", func(c *gin.Context) {
+				ginErr := c.Error(tt.err)
+				if tt.bind {
+					ginErr.SetType(gin.ErrorTypeBind)
+				}
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/42", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+
+			var problem middleware.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, middleware.Problem{
+				Type:     "about:blank",
+				Title:    http.StatusText(tt.wantStatus),
+				Status:   tt.wantStatus,
+				Detail:   tt.wantDetail,
+				Code:     tt.wantCode,
+				Instance: "/events/42",
+			}, problem)
+		})
+	}
+}
+
+func TestErrorHandlerRendersLastError(t *testing.T) {
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/", func(c *gin.Context) {
+		_ = c.Error(domain.ErrEventNotFound)
+		_ = c.Error(domain.ErrPermissionDenied)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestErrorHandlerKeepsWrittenResponse(t *testing.T) {
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/", func(c *gin.Context) {
+		_ = c.Error(domain.ErrEventNotFound)
+		c.JSON(http.StatusAccepted, gin.H{"message": "queued"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"message": "queued"}`, w.Body.String())
+}
+
+func TestErrorHandlerWithoutError(t *testing.T) {
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"domain error", domain.ErrInvalidMagicLink, domain.ErrInvalidMagicLink.Message},
+		{"wrapped domain error", fmt.Errorf("consume: %w", domain.ErrInvalidMagicLink), domain.ErrInvalidMagicLink.Message},
+		{"other error", errors.New("dial tcp: timeout"), "an unexpected error occurred, please try again later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, middleware.ErrorMessage(tt.err))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// RestaurantHeader selects the restaurant a request acts for when the user belongs to several
+const RestaurantHeader = "X-Restaurant-ID"
+
+// OrganizationHeader is the same as RestaurantHeader for organizations of any type
+const OrganizationHeader = "X-Organization-ID"
+
+type MembershipMiddleware struct {
+	membershipService ports.MembershipService
+}
+
+func NewMembershipMiddleware(membershipService ports.MembershipService) *MembershipMiddleware {
+	return &MembershipMiddleware{
+		membershipService: membershipService,
+	}
+}
+
+// ResolveRestaurant loads the membership of the user in the restaurant the request acts for
+// and stores it together with the restaurant in the context. It must run after Authenticate.
+func (m *MembershipMiddleware) ResolveRestaurant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// API keys belong to one restaurant, APIKeyMiddleware resolved it
+		if _, ok := c.Get("api_key"); ok {
+			c.Next()
+			return
+		}
+
+		userID := c.GetString("user_id")
+		if userID == "" {
+			abortWithError(c, domain.ErrAuthenticationRequired)
+			return
+		}
+
+		restaurantID := c.GetHeader(OrganizationHeader)
+		if restaurantID == "" {
+			restaurantID = c.GetHeader(RestaurantHeader)
+		}
+
+		member, err := m.membershipService.ResolveMembership(c.Request.Context(), userID, restaurantID)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Set("membership", member)
+		c.Set("restaurant", member.Restaurant)
+		c.Next()
+	}
+}
+
+// RequirePermission only lets through members whose role grants all the given permissions,
+// requests with an API key also need a scope granting them. It must run after ResolveRestaurant.
+func (m *MembershipMiddleware) RequirePermission(permissions ...domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("membership")
+		member, ok := value.(*domain.RestaurantMember)
+		if !exists || !ok {
+			forbidden(c)
+			return
+		}
+
+		value, _ = c.Get("api_key")
+		key, isAPIKey := value.(*domain.APIKey)
+
+		for _, p := range permissions {
+			if !member.Role.Can(p) || (isAPIKey && !key.Allows(p)) {
+				forbidden(c)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin/middleware"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        domain.MembershipRole
+		noMember    bool
+		scopes      domain.APIKeyScopes // nil for a session
+		permissions []domain.Permission
+		wantStatus  int
+	}{
+		{"session with the role's permission", domain.MembershipRoleStaff, false, nil, []domain.Permission{domain.PermissionRecordMeals}, http.StatusOK},
+		{"session without the role's permission", domain.MembershipRoleStaff, false, nil, []domain.Permission{domain.PermissionManageEvents}, http.StatusForbidden},
+		{"session needs every permission", domain.MembershipRoleManager, false, nil, []domain.Permission{domain.PermissionManageEvents, domain.PermissionManageMembers}, http.StatusForbidden},
+		{"no membership", "", true, nil, []domain.Permission{domain.PermissionViewRestaurant}, http.StatusForbidden},
+		{"key with a granting scope", domain.MembershipRoleOwner, false, domain.APIKeyScopes{domain.APIKeyScopeMealsWrite}, []domain.Permission{domain.PermissionRecordMeals}, http.StatusOK},
+		{"key without a granting scope", domain.MembershipRoleOwner, false, domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, []domain.Permission{domain.PermissionManageEvents}, http.StatusForbidden},
+		{"key scope beyond its creator's role", domain.MembershipRoleStaff, false, domain.APIKeyScopes{domain.APIKeyScopeEventsWrite}, []domain.Permission{domain.PermissionManageEvents}, http.StatusForbidden},
+		{"key never manages members", domain.MembershipRoleOwner, false, domain.APIKeyScopes{domain.APIKeyScopeEventsRead, domain.APIKeyScopeEventsWrite, domain.APIKeyScopeMealsWrite}, []domain.Permission{domain.PermissionManageMembers}, http.StatusForbidden},
+		{"key without scopes", domain.MembershipRoleOwner, false, domain.APIKeyScopes{}, []domain.Permission{domain.PermissionViewRestaurant}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			membershipMiddleware := middleware.NewMembershipMiddleware(nil)
+
+			router := gin.New()
+			router.Use(middleware.ErrorHandler())
+			router.Use(func(c *gin.Context) {
+				if !tt.noMember {
+					c.Set("membership", &domain.RestaurantMember{Role: tt.role})
+				}
+				if tt.scopes != nil {
+					c.Set("api_key", &domain.APIKey{Scopes: tt.scopes})
+				}
+			})
+			router.GET("/", membershipMiddleware.RequirePermission(tt.permissions...), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -1,30 +1,64 @@
 package gin
 
 import (
+	"fmt"
+
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin/handlers"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin/middleware"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/jwt"
 	"github.com/gin-gonic/gin"
 )
 
 func NewRouter(
 	authService ports.AuthService,
+	accountService ports.AccountService,
+	twoFactorService ports.TwoFactorService,
+	magicLinkService ports.MagicLinkService,
+	lockoutService ports.LockoutService,
+	impersonationService ports.ImpersonationService,
 	userService ports.UserService,
 	restaurantService ports.RestaurantService,
 	eventService ports.EventService,
 	volunteerService ports.VolunteerService,
+	inventoryService ports.InventoryService,
+	adminService ports.AdminService,
+	verificationService ports.VerificationService,
+	membershipService ports.MembershipService,
+	apiKeyService ports.APIKeyService,
+	auditService ports.AuditService,
+	jwtService *jwt.Service,
 	cfg *config.Config,
-) *gin.Engine {
+) (*gin.Engine, error) {
 	router := gin.Default()
 
+	// The login limits and the audit log go by the client IP, which must not come from a
+	// header any caller can set
+	if err := router.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
 	router.Use(middleware.CORSMiddleware(cfg))
+	router.Use(middleware.AuditContextMiddleware())
+	router.Use(middleware.ErrorHandler())
 
 	// Middlewares
-	authMiddleware := middleware.NewAuthMiddleware(authService)
+	authMiddleware := middleware.NewAuthMiddleware(authService, twoFactorService, auditService)
+	membershipMiddleware := middleware.NewMembershipMiddleware(membershipService)
+	apiKeyMiddleware := middleware.NewAPIKeyMiddleware(apiKeyService)
 
 	// Handlers
-	authHandler := handlers.NewAuthHandler(authService, cfg)
+	authHandler := handlers.NewAuthHandler(
+		authService,
+		accountService,
+		twoFactorService,
+		magicLinkService,
+		lockoutService,
+		impersonationService,
+		cfg,
+	)
 	userHandler := handlers.NewUserHandler(userService)
 	swaggerHandler := handlers.NewSwaggerHandler()
 	restaurantHandler := handlers.NewRestaurantHandler(
@@ -33,56 +67,192 @@ func NewRouter(
 		volunteerService,
 	)
 	volunteerHandler := handlers.NewVolunteerHandler(volunteerService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService, eventService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	verificationHandler := handlers.NewVerificationHandler(verificationService, cfg)
+	membershipHandler := handlers.NewMembershipHandler(membershipService)
+	branchHandler := handlers.NewBranchHandler(restaurantService)
+	jwksHandler := handlers.NewJWKSHandler(jwtService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	auditHandler := handlers.NewAuditHandler(auditService)
 	v1 := router.Group("/api/v1")
 	{
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/register_restaurant", authHandler.RegisterRestaurant)
+			auth.POST("/register_organization", authHandler.RegisterOrganization)
 			auth.POST("/register_volunteer", authHandler.RegisterVolunteer)
+			auth.POST("/register_staff", authHandler.RegisterStaff)
 			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-			auth.POST("/logout", authHandler.Logout)
+			auth.POST("/login/two_factor", authHandler.CompleteTwoFactorLogin)
+			auth.GET("/oidc/:provider", authHandler.StartOIDCLogin)
+			auth.GET("/oidc/:provider/callback", authHandler.OIDCCallback)
+			auth.POST("/refresh", middleware.RequireRefreshCSRF(), authHandler.RefreshToken)
+			auth.POST("/logout", middleware.RequireRefreshCSRF(), authHandler.Logout)
+			auth.POST("/magic-link", authHandler.RequestMagicLink)
+			auth.POST("/magic-link/verify", authHandler.VerifyMagicLink)
+			auth.POST("/forgot_password", authHandler.ForgotPassword)
+			auth.POST("/reset_password", authHandler.ResetPassword)
+			auth.POST("/unlock_account", authHandler.UnlockAccount)
+			auth.POST("/verify_email", authHandler.VerifyEmail)
+			auth.POST("/verify_business_email", authHandler.VerifyBusinessEmail)
+			auth.POST("/confirm_email_change", authHandler.ConfirmEmailChange)
 		}
 
 		users := v1.Group("/user")
-		users.Use(authMiddleware.Authenticate())
+		users.Use(authMiddleware.Authenticate(), authMiddleware.RequirePermission(domain.PermissionManageProfile))
 		{
 			users.GET("/me", userHandler.GetMe)
 			users.PUT("/me", userHandler.UpdateMe)
+			users.GET("/export", authMiddleware.ForbidImpersonation(), userHandler.ExportData)
+			users.POST("/deletion", authMiddleware.ForbidImpersonation(), userHandler.RequestDeletion)
+			users.DELETE("/deletion", authMiddleware.ForbidImpersonation(), userHandler.CancelDeletion)
+			users.POST("/verification_email", authHandler.ResendVerificationEmail)
+			users.POST("/password", authMiddleware.ForbidImpersonation(), authHandler.ChangePassword)
+			users.POST("/email", authMiddleware.ForbidImpersonation(), authHandler.ChangeEmail)
+			users.GET("/sessions", authHandler.GetSessions)
+			users.DELETE("/sessions", authMiddleware.ForbidImpersonation(), authHandler.RevokeOtherSessions)
+			users.DELETE("/sessions/:id", authMiddleware.ForbidImpersonation(), authHandler.RevokeSession)
+			users.POST("/two_factor/setup", authMiddleware.ForbidImpersonation(), authHandler.SetupTwoFactor)
+			users.POST("/two_factor/enable", authMiddleware.ForbidImpersonation(), authHandler.EnableTwoFactor)
+			users.POST("/two_factor/disable", authMiddleware.ForbidImpersonation(), authHandler.DisableTwoFactor)
+			users.POST("/two_factor/recovery_codes", authMiddleware.ForbidImpersonation(), authHandler.RegenerateRecoveryCodes)
+			users.GET("/memberships", membershipHandler.GetMemberships)
+			users.POST("/invitations/accept", authMiddleware.ForbidImpersonation(), membershipHandler.AcceptInvitation)
+			users.DELETE("/impersonation", authHandler.EndImpersonation)
 		}
 
-		restaurant := v1.Group("/restaurant")
-		restaurant.Use(authMiddleware.Authenticate())
-		{
-			restaurant.GET("/dashboard", restaurantHandler.GetDashboard)
-			restaurant.GET("/", restaurantHandler.GetRestaurant)
-			restaurant.POST("/events", restaurantHandler.CreateEvent)
-			restaurant.GET("/events/:id", restaurantHandler.GetEvent)
-			restaurant.PUT("/events/:id", restaurantHandler.UpdateEvent)
-			restaurant.DELETE("/events/:id", restaurantHandler.DeleteEvent)
-			restaurant.PATCH("/events/:id/status", restaurantHandler.UpdateEventStatus)
-			restaurant.PATCH("/events/:id/guests", restaurantHandler.UpdateGuestCount)
-			restaurant.PATCH("/events/:id/meals", restaurantHandler.UpdateMealsServed)
-
-			restaurant.GET("/applications", restaurantHandler.GetVolunteerApplications)
-			restaurant.POST("/applications/:id/approve", restaurantHandler.ApproveVolunteerApplication)
-			restaurant.POST("/applications/:id/decline", restaurantHandler.DeclineVolunteerApplication)
+		// Organizations of every type share these routes, /restaurant is kept for existing clients.
+		// Their own tools may call them with an API key instead of a session.
+		for _, path := range []string{"/restaurant", "/organization"} {
+			restaurant := v1.Group(path)
+			restaurant.Use(apiKeyMiddleware.Authenticate(), authMiddleware.Authenticate(), authMiddleware.RequireRole(domain.UserTypeRestaurant), membershipMiddleware.ResolveRestaurant(), authMiddleware.RequireTwoFactorEnrollment())
+			{
+				restaurant.GET("/dashboard", membershipMiddleware.RequirePermission(domain.PermissionViewRestaurant), restaurantHandler.GetDashboard)
+				restaurant.GET("/", membershipMiddleware.RequirePermission(domain.PermissionViewRestaurant), restaurantHandler.GetRestaurant)
+
+				verification := restaurant.Group("/verification")
+				verification.Use(membershipMiddleware.RequirePermission(domain.PermissionManageRestaurant))
+				{
+					verification.GET("", verificationHandler.GetVerification)
+					verification.POST("/documents", verificationHandler.UploadDocument)
+					verification.DELETE("/documents/:id", verificationHandler.DeleteDocument)
+					verification.POST("/business_email", authHandler.ResendBusinessVerificationEmail)
+				}
+
+				branches := restaurant.Group("/branches")
+				{
+					branches.GET("", membershipMiddleware.RequirePermission(domain.PermissionViewRestaurant), branchHandler.GetBranches)
+					branches.POST("", membershipMiddleware.RequirePermission(domain.PermissionManageRestaurant), branchHandler.CreateBranch)
+					branches.PUT("/:id", membershipMiddleware.RequirePermission(domain.PermissionManageRestaurant), branchHandler.UpdateBranch)
+					branches.DELETE("/:id", membershipMiddleware.RequirePermission(domain.PermissionManageRestaurant), branchHandler.DeleteBranch)
+				}
+
+				apiKeys := restaurant.Group("/api_keys")
+				apiKeys.Use(membershipMiddleware.RequirePermission(domain.PermissionManageRestaurant), authMiddleware.ForbidImpersonation())
+				{
+					apiKeys.GET("", apiKeyHandler.GetAPIKeys)
+					apiKeys.POST("", apiKeyHandler.CreateAPIKey)
+					apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+				}
+
+				members := restaurant.Group("/members")
+				members.Use(membershipMiddleware.RequirePermission(domain.PermissionManageMembers), authMiddleware.ForbidImpersonation())
+				{
+					members.GET("", membershipHandler.GetMembers)
+					members.PATCH("/:id", membershipHandler.UpdateMember)
+					members.DELETE("/:id", membershipHandler.RemoveMember)
+					members.GET("/invitations", membershipHandler.GetInvitations)
+					members.POST("/invitations", membershipHandler.InviteMember)
+					members.DELETE("/invitations/:id", membershipHandler.RevokeInvitation)
+				}
+
+				events := restaurant.Group("/events")
+				events.Use(membershipMiddleware.RequirePermission(domain.PermissionViewEvents))
+				{
+					events.POST("", authMiddleware.RequireVerifiedEmail(), membershipMiddleware.RequirePermission(domain.PermissionManageEvents), restaurantHandler.CreateEvent)
+					events.GET("/:id", restaurantHandler.GetEvent)
+					events.PUT("/:id", membershipMiddleware.RequirePermission(domain.PermissionManageEvents), restaurantHandler.UpdateEvent)
+					events.DELETE("/:id", membershipMiddleware.RequirePermission(domain.PermissionManageEvents), restaurantHandler.DeleteEvent)
+					events.GET("/:id/hosts", membershipMiddleware.RequirePermission(domain.PermissionManageEvents), restaurantHandler.GetEventHosts)
+					events.POST("/:id/hosts", membershipMiddleware.RequirePermission(domain.PermissionManageEvents), restaurantHandler.AddEventHost)
+					events.PUT("/:id/hosts/:host_id", membershipMiddleware.RequirePermission(domain.PermissionManageEvents), restaurantHandler.UpdateEventHost)
+					events.DELETE("/:id/hosts/:host_id", membershipMiddleware.RequirePermission(domain.PermissionManageEvents), restaurantHandler.RemoveEventHost)
+					events.PATCH("/:id/status", membershipMiddleware.RequirePermission(domain.PermissionManageEvents), restaurantHandler.UpdateEventStatus)
+					events.PATCH("/:id/guests", membershipMiddleware.RequirePermission(domain.PermissionManageEvents), restaurantHandler.UpdateGuestCount)
+					events.PATCH("/:id/meals", membershipMiddleware.RequirePermission(domain.PermissionRecordMeals), restaurantHandler.UpdateMealsServed)
+					events.GET("/:id/meals", membershipMiddleware.RequirePermission(domain.PermissionRecordMeals), restaurantHandler.GetMealLog)
+					events.POST("/:id/meals", membershipMiddleware.RequirePermission(domain.PermissionRecordMeals), restaurantHandler.RecordMeals)
+					events.POST("/:id/meals/:entry_id/correct", membershipMiddleware.RequirePermission(domain.PermissionRecordMeals), restaurantHandler.CorrectMealEntry)
+					events.POST("/:id/consumption", membershipMiddleware.RequirePermission(domain.PermissionManageInventory), inventoryHandler.RecordConsumption)
+					events.GET("/:id/inventory", membershipMiddleware.RequirePermission(domain.PermissionManageInventory), inventoryHandler.GetEventSummary)
+				}
+
+				inventory := restaurant.Group("/inventory")
+				inventory.Use(membershipMiddleware.RequirePermission(domain.PermissionManageInventory))
+				{
+					inventory.GET("", inventoryHandler.GetInventory)
+					inventory.POST("", inventoryHandler.AddItem)
+					inventory.GET("/expiring", inventoryHandler.GetExpiringItems)
+					inventory.PUT("/:id", inventoryHandler.UpdateItem)
+					inventory.DELETE("/:id", inventoryHandler.DeleteItem)
+				}
+
+				applications := restaurant.Group("/applications")
+				applications.Use(membershipMiddleware.RequirePermission(domain.PermissionReviewApplications))
+				{
+					applications.GET("", restaurantHandler.GetVolunteerApplications)
+					applications.POST("/:id/approve", restaurantHandler.ApproveVolunteerApplication)
+					applications.POST("/:id/decline", restaurantHandler.DeclineVolunteerApplication)
+				}
+			}
 		}
 
 		volunteer := v1.Group("/volunteer")
-		volunteer.Use(authMiddleware.Authenticate())
+		volunteer.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole(domain.UserTypeVolunteer))
 		{
 			volunteer.GET("/dashboard", volunteerHandler.GetVolunteerDashboard)
 			volunteer.GET("/upcoming-tasks", volunteerHandler.GetUpcomingTasks)
 			volunteer.GET("/nearby-opportunities", volunteerHandler.GetNearbyOpportunities)
 			volunteer.GET("/badges", volunteerHandler.GetVolunteerBadges)
-			volunteer.POST("/events/:id/apply", volunteerHandler.ApplyForEvent)
-			volunteer.POST("/events/:id/check-in", volunteerHandler.CheckInForEvent)
+			volunteer.POST("/events/:id/apply", authMiddleware.RequirePermission(domain.PermissionApplyForEvents), authMiddleware.RequireVerifiedEmail(), volunteerHandler.ApplyForEvent)
+			volunteer.POST("/events/:id/check-in", authMiddleware.RequirePermission(domain.PermissionCheckIn), volunteerHandler.CheckInForEvent)
+		}
+
+		admin := v1.Group("/admin")
+		admin.Use(authMiddleware.Authenticate(), authMiddleware.RequirePermission(domain.PermissionAdminister))
+		{
+			admin.GET("/users", adminHandler.ListUsers)
+			admin.GET("/users/:id", adminHandler.GetUser)
+			admin.PATCH("/users/:id", adminHandler.UpdateUser)
+			admin.POST("/users/:id/suspend", adminHandler.SuspendUser)
+			admin.POST("/users/:id/reactivate", adminHandler.ReactivateUser)
+			admin.POST("/users/:id/impersonate", authHandler.Impersonate)
+
+			admin.GET("/restaurants", adminHandler.ListRestaurants)
+			admin.GET("/restaurants/:id", adminHandler.GetRestaurant)
+			admin.PUT("/restaurants/:id", adminHandler.UpdateRestaurant)
+			admin.GET("/restaurants/:id/documents", verificationHandler.GetRestaurantDocuments)
+			admin.POST("/restaurants/:id/approve", verificationHandler.ApproveRestaurant)
+			admin.POST("/restaurants/:id/reject", verificationHandler.RejectRestaurant)
+			admin.GET("/documents/:id", verificationHandler.DownloadDocument)
+
+			admin.GET("/events", adminHandler.ListEvents)
+			admin.PUT("/events/:id", adminHandler.UpdateEvent)
+
+			admin.GET("/applications", adminHandler.ListApplications)
+			admin.PATCH("/applications/:id/status", adminHandler.UpdateApplicationStatus)
+
+			admin.GET("/security_policy", adminHandler.GetSecurityPolicy)
+			admin.PUT("/security_policy", adminHandler.UpdateSecurityPolicy)
+
+			admin.GET("/audit_logs", auditHandler.ListAuditLogs)
 		}
 	}
 
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
 	router.GET("/swagger.yaml", swaggerHandler.SetupSwagger)
 	router.GET("/swagger/*any", swaggerHandler.SetupSwaggerUI)
 
-	return router
+	return router, nil
 }
@@ -0,0 +1,83 @@
+package gin_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
+	router "github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/http/gin/middleware"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// scopedKeyService authenticates every key as one of a manager with the given scopes
+type scopedKeyService struct {
+	ports.APIKeyService
+	scopes domain.APIKeyScopes
+}
+
+func (s scopedKeyService) AuthenticateAPIKey(ctx context.Context, rawKey string) (*domain.APIKey, *domain.User, *domain.RestaurantMember, error) {
+	restaurant := &domain.Restaurant{ID: uuid.New()}
+	user := &domain.User{ID: uuid.New(), Type: domain.UserTypeRestaurant}
+	member := &domain.RestaurantMember{
+		UserID:       user.ID,
+		RestaurantID: restaurant.ID,
+		Role:         domain.MembershipRoleManager,
+		Restaurant:   restaurant,
+	}
+	return &domain.APIKey{ID: uuid.New(), RestaurantID: restaurant.ID, Scopes: s.scopes}, user, member, nil
+}
+
+// missingEventService finds no events, a 404 means the request got past the permissions
+type missingEventService struct {
+	ports.EventService
+}
+
+func (s missingEventService) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
+	return nil, domain.ErrEventNotFound
+}
+
+func TestAPIKeyRoutes(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     domain.APIKeyScopes
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"events:read reads events", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, http.MethodGet, "/api/v1/restaurant/events/" + uuid.NewString(), http.StatusNotFound},
+		{"events:read has no dashboard", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, http.MethodGet, "/api/v1/restaurant/dashboard", http.StatusForbidden},
+		{"events:read has no applications", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, http.MethodGet, "/api/v1/restaurant/applications", http.StatusForbidden},
+		{"events:read has no restaurant", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, http.MethodGet, "/api/v1/organization/", http.StatusForbidden},
+		{"every scope has no dashboard", domain.APIKeyScopes{domain.APIKeyScopeEventsRead, domain.APIKeyScopeEventsWrite, domain.APIKeyScopeMealsWrite}, http.MethodGet, "/api/v1/restaurant/dashboard", http.StatusForbidden},
+		{"every scope has no applications", domain.APIKeyScopes{domain.APIKeyScopeEventsRead, domain.APIKeyScopeEventsWrite, domain.APIKeyScopeMealsWrite}, http.MethodGet, "/api/v1/restaurant/applications", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := router.NewRouter(
+				nil, nil, nil, nil, nil, nil, nil, nil, missingEventService{}, nil, nil, nil, nil, nil, scopedKeyService{scopes: tt.scopes}, nil, nil,
+				&config.Config{},
+			)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(middleware.APIKeyHeader, "dcf_test_key")
+
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
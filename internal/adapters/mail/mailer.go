@@ -0,0 +1,100 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// NewMailer returns an SMTP mailer. When no SMTP host is configured the emails are
+// written to the outbox directory, or only logged, so development setups and tests work
+// without a mail server. Production requires an SMTP host.
+func NewMailer(cfg *config.Config) (ports.Mailer, error) {
+	if cfg.Mail.Host == "" {
+		if cfg.Server.Environment == "prod" {
+			return nil, fmt.Errorf("no SMTP host configured")
+		}
+		if cfg.Mail.OutboxDir != "" {
+			return &fileMailer{dir: cfg.Mail.OutboxDir, from: cfg.Mail.From}, nil
+		}
+		return &logMailer{}, nil
+	}
+
+	return &smtpMailer{
+		addr: fmt.Sprintf("%s:%d", cfg.Mail.Host, cfg.Mail.Port),
+		from: cfg.Mail.From,
+		auth: smtp.PlainAuth("", cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.Host),
+	}, nil
+}
+
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func (m *smtpMailer) Send(ctx context.Context, to, subject, body string) error {
+	// Header values must not contain line breaks
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+
+	msg := buildMessage(m.from, to, subject, body)
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// logMailer only logs who an email is for, the bodies hold sign-in and reset tokens
+type logMailer struct{}
+
+func (m *logMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("Email to %s: %s (not sent, no SMTP host or outbox configured)", to, subject)
+	return nil
+}
+
+// fileMailer writes every email to its own .eml file, which makes the links in them easy
+// to pick up in development and tests
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+func (m *fileMailer) Send(ctx context.Context, to, subject, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create outbox: %w", err)
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), uuid.NewString())
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(buildMessage(m.from, to, subject, body)), 0o600); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	return nil
+}
+
+func buildMessage(from, to, subject, body string) string {
+	return strings.Join([]string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+}
@@ -0,0 +1,223 @@
+// Package fakeidp is an OpenID Connect provider for local development and integration tests.
+// It signs everyone in without asking, as the configured user or the one named by the
+// login_hint parameter. Never expose it outside a test setup.
+package fakeidp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	keyID   = "fakeidp"
+	codeTTL = time.Minute
+	idTTL   = 5 * time.Minute
+)
+
+// User is who the provider signs in
+type User struct {
+	Email         string
+	Name          string
+	EmailVerified bool
+}
+
+type Server struct {
+	issuer string
+	user   User
+	key    *rsa.PrivateKey
+	mux    *http.ServeMux
+
+	mu    sync.Mutex
+	codes map[string]*authorization
+}
+
+// authorization is a code handed out by /authorize, waiting to be exchanged at /token
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	user          User
+	expiresAt     time.Time
+}
+
+// New returns a provider for the issuer URL it is served at, signing in user by default
+func New(issuer string, user User) (*Server, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		issuer: issuer,
+		user:   user,
+		key:    key,
+		mux:    http.NewServeMux(),
+		codes:  make(map[string]*authorization),
+	}
+	s.mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	s.mux.HandleFunc("/jwks", s.jwks)
+	s.mux.HandleFunc("/authorize", s.authorize)
+	s.mux.HandleFunc("/token", s.token)
+
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.issuer,
+		"authorization_endpoint":                s.issuer + "/authorize",
+		"token_endpoint":                        s.issuer + "/token",
+		"jwks_uri":                              s.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	public := s.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize approves every request right away and redirects back with a code
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "only the authorization code flow with S256 PKCE is supported", http.StatusBadRequest)
+		return
+	}
+
+	user := s.user
+	if hint := query.Get("login_hint"); hint != "" {
+		user = User{Email: hint, Name: hint, EmailVerified: true}
+	}
+
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = &authorization{
+		clientID:      query.Get("client_id"),
+		redirectURI:   query.Get("redirect_uri"),
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		user:          user,
+		expiresAt:     time.Now().Add(codeTTL),
+	}
+	s.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	auth, ok := s.codes[code]
+	delete(s.codes, code)
+	s.mu.Unlock()
+
+	if !ok || time.Now().After(auth.expiresAt) || r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "invalid_grant")
+		return
+	}
+	if r.PostForm.Get("client_id") != auth.clientID || r.PostForm.Get("redirect_uri") != auth.redirectURI {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(auth.codeChallenge)) != 1 {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	subject := sha256.Sum256([]byte(auth.user.Email))
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            s.issuer,
+		"sub":            hex.EncodeToString(subject[:8]),
+		"aud":            auth.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(idTTL).Unix(),
+		"nonce":          auth.nonce,
+		"email":          auth.user.Email,
+		"email_verified": auth.user.EmailVerified,
+		"name":           auth.user.Name,
+	})
+	idToken.Header["kid"] = keyID
+
+	signed, err := idToken.SignedString(s.key)
+	if err != nil {
+		tokenError(w, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(idTTL.Seconds()),
+		"id_token":     signed,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
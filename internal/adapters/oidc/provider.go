@@ -0,0 +1,284 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Keys are fetched again at most this often when a token names an unknown key
+const keyRefreshInterval = time.Minute
+
+// NewProviders returns the configured providers by name
+func NewProviders(cfg *config.Config) map[string]ports.IdentityProvider {
+	providers := make(map[string]ports.IdentityProvider, len(cfg.OIDC.Providers))
+	for _, p := range cfg.OIDC.Providers {
+		providers[p.Name] = NewProvider(p)
+	}
+	return providers
+}
+
+// NewProvider returns a provider that reads its discovery document on first use, so the API
+// starts even while a provider is unreachable
+func NewProvider(cfg config.OIDCProviderConfig) ports.IdentityProvider {
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &provider{
+		cfg:    cfg,
+		scopes: scopes,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type provider struct {
+	cfg    config.OIDCProviderConfig
+	scopes []string
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string      `json:"nonce"`
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"` // some providers send the string "true"
+	Name          string      `json:"name"`
+}
+
+func (p *provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return doc.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.ExternalClaims, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the identity provider: %w", err)
+	}
+	defer res.Body.Close()
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the identity provider refused the code: %s %s", tokens.Error, tokens.ErrorDescription)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("the identity provider returned no ID token")
+	}
+
+	return p.verifyIDToken(ctx, doc, tokens.IDToken, nonce)
+}
+
+func (p *provider) verifyIDToken(ctx context.Context, doc *discoveryDocument, rawToken, nonce string) (*domain.ExternalClaims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(rawToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := p.key(ctx, doc, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		// The algorithm must match the key, never what the token claims on its own
+		var ok bool
+		switch key.(type) {
+		case *rsa.PublicKey:
+			_, ok = t.Method.(*jwt.SigningMethodRSA)
+		case *ecdsa.PublicKey:
+			_, ok = t.Method.(*jwt.SigningMethodECDSA)
+		case ed25519.PublicKey:
+			_, ok = t.Method.(*jwt.SigningMethodEd25519)
+		}
+		if !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	if claims.Issuer != doc.Issuer {
+		return nil, errors.New("invalid ID token: wrong issuer")
+	}
+	if !claims.VerifyAudience(p.cfg.ClientID, true) {
+		return nil, errors.New("invalid ID token: wrong audience")
+	}
+	if claims.ExpiresAt == nil {
+		return nil, errors.New("invalid ID token: no expiry")
+	}
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return nil, errors.New("invalid ID token: wrong nonce")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid ID token: no subject")
+	}
+
+	verified := false
+	switch v := claims.EmailVerified.(type) {
+	case bool:
+		verified = v
+	case string:
+		verified = v == "true"
+	}
+
+	return &domain.ExternalClaims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: verified,
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var doc discoveryDocument
+	if err := p.getJSON(ctx, strings.TrimRight(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, fmt.Errorf("failed to read the identity provider configuration: %w", err)
+	}
+	if doc.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("the identity provider reports issuer %q, expected %q", doc.Issuer, p.cfg.Issuer)
+	}
+
+	p.discovery = &doc
+	return p.discovery, nil
+}
+
+// key returns the provider's key with the ID, fetching the keys again when it is unknown
+// since providers rotate their keys
+func (p *provider) key(ctx context.Context, doc *discoveryDocument, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key := p.lookupKey(kid); key != nil {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetchedAt) < keyRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	var set jwkSet
+	if err := p.getJSON(ctx, doc.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to read the identity provider keys: %w", err)
+	}
+	p.keys = set.publicKeys()
+	p.keysFetchedAt = time.Now()
+
+	if key := p.lookupKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// lookupKey finds a key by ID, a token without an ID matches a provider's only key
+func (p *provider) lookupKey(kid string) interface{} {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+	return p.keys[kid]
+}
+
+func (p *provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", res.StatusCode, url)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
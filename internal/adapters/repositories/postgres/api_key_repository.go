@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) ports.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, tx interface{}, key *domain.APIKey) error {
+	if tx == nil {
+		return r.db.Create(key).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(key).Error
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.db.First(&key, "id = ?", id).Error; err != nil {
+		return nil, notFound(err, domain.ErrAPIKeyNotFound)
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.db.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, notFound(err, domain.ErrAPIKeyNotFound)
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	if err := r.db.Where("restaurant_id = ?", restaurantID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, tx interface{}, id uuid.UUID) error {
+	if tx == nil {
+		return r.db.Model(&domain.APIKey{}).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now()).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return r.db.Model(&domain.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+}
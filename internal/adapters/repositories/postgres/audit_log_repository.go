@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) ports.AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, tx interface{}, entry *domain.AuditLog) error {
+	if tx == nil {
+		return r.db.Create(entry).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(entry).Error
+}
+
+func (r *auditLogRepository) List(ctx context.Context, filter domain.AuditLogFilter) ([]*domain.AuditLog, int, error) {
+	var entries []*domain.AuditLog
+	var count int64
+
+	query := r.db.Model(&domain.AuditLog{})
+
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ? OR impersonator_id = ?", filter.ActorID, filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Limit(filter.Limit).Offset(filter.Offset).Order("created_at DESC").Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, int(count), nil
+}
+
+func (r *auditLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&domain.AuditLog{})
+	return int(result.RowsAffected), result.Error
+}
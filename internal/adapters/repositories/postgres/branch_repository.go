@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type branchRepository struct {
+	db *gorm.DB
+}
+
+func NewBranchRepository(db *gorm.DB) ports.BranchRepository {
+	return &branchRepository{db: db}
+}
+
+func (r *branchRepository) Create(ctx context.Context, tx interface{}, branch *domain.Branch) error {
+	if tx == nil {
+		return r.db.Create(branch).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(branch).Error
+}
+
+func (r *branchRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Branch, error) {
+	var branch domain.Branch
+	if err := r.db.Where("id = ?", id).First(&branch).Error; err != nil {
+		return nil, notFound(err, domain.ErrBranchNotFound)
+	}
+	return &branch, nil
+}
+
+func (r *branchRepository) GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.Branch, error) {
+	var branches []*domain.Branch
+	if err := r.db.Where("restaurant_id = ?", restaurantID).Order("created_at ASC").Find(&branches).Error; err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+func (r *branchRepository) Update(ctx context.Context, tx interface{}, branch *domain.Branch) error {
+	if tx == nil {
+		return r.db.Save(branch).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Save(branch).Error
+}
+
+func (r *branchRepository) Delete(ctx context.Context, tx interface{}, id uuid.UUID) error {
+	if tx == nil {
+		return r.db.Delete(&domain.Branch{}, "id = ?", id).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Delete(&domain.Branch{}, "id = ?", id).Error
+}
+
+func (r *branchRepository) GetStats(ctx context.Context, restaurantID uuid.UUID) ([]*domain.BranchStats, error) {
+	var stats []*domain.BranchStats
+
+	err := r.db.Raw(`
+		SELECT b.id AS branch_id, b.name,
+			COUNT(e.id) AS total_events,
+			COUNT(e.id) FILTER (WHERE e.status = ?) AS upcoming_events,
+			COALESCE(SUM(e.meals_served), 0) AS meals_served,
+			(SELECT COUNT(DISTINCT ev.volunteer_id) FROM event_volunteers ev
+				JOIN events ve ON ve.id = ev.event_id AND ve.deleted_at IS NULL
+				WHERE ve.branch_id = b.id AND ev.deleted_at IS NULL) AS volunteers_engaged
+		FROM branches b
+		LEFT JOIN events e ON e.branch_id = b.id AND e.deleted_at IS NULL
+		WHERE b.restaurant_id = ? AND b.deleted_at IS NULL
+		GROUP BY b.id, b.name, b.created_at
+		ORDER BY b.created_at ASC
+	`, domain.EventStatusUpcoming, restaurantID).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -23,25 +23,111 @@ func NewConnection(cfg *config.Config) (*gorm.DB, error) {
 	)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";")
 
+	// Accounts created before email verification existed are trusted as they are
+	backfillEmailVerification := !db.Migrator().HasColumn(&domain.User{}, "EmailVerifiedAt")
+	backfillBusinessEmail := !db.Migrator().HasColumn(&domain.Restaurant{}, "BusinessEmailVerifiedAt")
+
 	err = db.AutoMigrate(
 		&domain.User{},
 		&domain.Restaurant{},
+		&domain.Branch{},
+		&domain.RestaurantDocument{},
+		&domain.RestaurantMember{},
+		&domain.RestaurantInvitation{},
 		&domain.Volunteer{},
 		&domain.Event{},
+		&domain.EventHost{},
 		&domain.VolunteerApplication{},
 		&domain.EventVolunteer{},
+		&domain.MealLogEntry{},
+		&domain.InventoryItem{},
+		&domain.InventoryConsumption{},
+		&domain.LoginAttempt{},
+		&domain.RecoveryCode{},
+		&domain.SecurityPolicy{},
+		&domain.ExternalIdentity{},
+		&domain.APIKey{},
+		&domain.AuditLog{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	if backfillEmailVerification {
+		if err := db.Exec(`UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL`).Error; err != nil {
+			return nil, fmt.Errorf("failed to backfill email verification: %w", err)
+		}
+	}
+
+	// The business email was not stored before, the address of the owner stands in for it
+	if backfillBusinessEmail {
+		err = db.Exec(`
+			UPDATE restaurants r
+			SET business_email = COALESCE(NULLIF(r.business_email, ''), u.email), business_email_verified_at = NOW()
+			FROM users u
+			WHERE u.id = r.user_id
+			AND r.business_email_verified_at IS NULL
+		`).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to backfill business emails: %w", err)
+		}
+	}
+
+	// Seed the meal log with the counters of events recorded before it existed
+	err = db.Exec(`
+		INSERT INTO meal_log_entries (id, event_id, restaurant_id, recorded_by, count, note, created_at)
+		SELECT uuid_generate_v4(), e.id, e.restaurant_id, r.user_id, e.meals_served, 'opening balance', NOW()
+		FROM events e
+		JOIN restaurants r ON r.id = e.restaurant_id
+		WHERE e.meals_served > 0
+		AND NOT EXISTS (SELECT 1 FROM meal_log_entries m WHERE m.event_id = e.id)
+	`).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill meal log: %w", err)
+	}
+
+	// Make the user who registered each restaurant its owner
+	err = db.Exec(`
+		INSERT INTO restaurant_members (id, restaurant_id, user_id, role, created_at, updated_at)
+		SELECT uuid_generate_v4(), r.id, r.user_id, ?, r.created_at, NOW()
+		FROM restaurants r
+		WHERE r.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM restaurant_members m WHERE m.restaurant_id = r.id AND m.user_id = r.user_id)
+	`, domain.MembershipRoleOwner).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill restaurant members: %w", err)
+	}
+
+	// Give restaurants registered before branches existed a main branch holding their events
+	err = db.Exec(`
+		INSERT INTO branches (id, restaurant_id, name, address, contact_number, created_at, updated_at)
+		SELECT uuid_generate_v4(), r.id, r.name, COALESCE(r.address, ''), r.contact_number, r.created_at, NOW()
+		FROM restaurants r
+		WHERE r.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM branches b WHERE b.restaurant_id = r.id)
+	`).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill branches: %w", err)
+	}
+
+	err = db.Exec(`
+		UPDATE events e SET branch_id = (
+			SELECT b.id FROM branches b WHERE b.restaurant_id = e.restaurant_id ORDER BY b.created_at ASC LIMIT 1
+		)
+		WHERE e.branch_id IS NULL
+	`).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill event branches: %w", err)
+	}
+
 	log.Println("Database connected and migrations completed successfully")
 	return db, nil
 }
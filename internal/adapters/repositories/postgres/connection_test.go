@@ -0,0 +1,93 @@
+package postgres_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB connects to the database of TEST_DATABASE_DSN, the tests of this package need
+// a real Postgres for its locks and constraints and are skipped without one. Every test
+// seeds rows of its own, so they can share the database.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error)
+
+	err = db.AutoMigrate(
+		&domain.User{},
+		&domain.Restaurant{},
+		&domain.Branch{},
+		&domain.Event{},
+		&domain.EventHost{},
+		&domain.MealLogEntry{},
+		&domain.InventoryItem{},
+		&domain.InventoryConsumption{},
+		&domain.AuditLog{},
+	)
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+func seedRestaurant(t *testing.T, db *gorm.DB) *domain.Restaurant {
+	t.Helper()
+
+	suffix := uuid.NewString()
+	user := &domain.User{
+		Username: "owner-" + suffix,
+		Email:    "owner-" + suffix + "@example.com",
+		Password: "not a hash",
+		Type:     domain.UserTypeRestaurant,
+	}
+	require.NoError(t, db.Create(user).Error)
+
+	verifiedAt := time.Now()
+	restaurant := &domain.Restaurant{
+		UserID:                  user.ID,
+		Name:                    "Dar Zitoun",
+		BusinessEmail:           user.Email,
+		BusinessEmailVerifiedAt: &verifiedAt,
+	}
+	require.NoError(t, db.Create(restaurant).Error)
+
+	return restaurant
+}
+
+func seedEvent(t *testing.T, db *gorm.DB, restaurant *domain.Restaurant) *domain.Event {
+	t.Helper()
+
+	start := time.Now().Add(24 * time.Hour)
+	event := &domain.Event{
+		RestaurantID: restaurant.ID,
+		Title:        "Iftar",
+		Date:         start,
+		StartTime:    start,
+		EndTime:      start.Add(2 * time.Hour),
+		Status:       domain.EventStatusUpcoming,
+	}
+	require.NoError(t, db.Create(event).Error)
+
+	return event
+}
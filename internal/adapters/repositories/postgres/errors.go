@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// notFound replaces the error GORM returns for a missing row with the domain error of the
+// record, other errors are returned unchanged
+func notFound(err error, domainErr *domain.Error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domainErr
+	}
+	return err
+}
+
+// duplicate replaces the error of a unique constraint violation with the domain error of
+// the constraint, other errors are returned unchanged
+func duplicate(err error, domainErr *domain.Error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domainErr
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type eventHostRepository struct {
+	db *gorm.DB
+}
+
+func NewEventHostRepository(db *gorm.DB) ports.EventHostRepository {
+	return &eventHostRepository{db: db}
+}
+
+func (r *eventHostRepository) Create(ctx context.Context, tx interface{}, host *domain.EventHost) error {
+	if tx == nil {
+		return r.db.Create(host).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(host).Error
+}
+
+func (r *eventHostRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.EventHost, error) {
+	var host domain.EventHost
+	if err := r.db.Preload("Restaurant").Where("id = ?", id).First(&host).Error; err != nil {
+		return nil, notFound(err, domain.ErrEventHostNotFound)
+	}
+	return &host, nil
+}
+
+func (r *eventHostRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) ([]*domain.EventHost, error) {
+	var hosts []*domain.EventHost
+	if err := r.db.Preload("Restaurant").
+		Where("event_id = ?", eventID).
+		Order("created_at ASC").
+		Find(&hosts).Error; err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+func (r *eventHostRepository) Update(ctx context.Context, tx interface{}, host *domain.EventHost) error {
+	updates := map[string]interface{}{
+		"can_edit_details":        host.CanEditDetails,
+		"can_review_applications": host.CanReviewApplications,
+		"can_record_meals":        host.CanRecordMeals,
+	}
+
+	if tx == nil {
+		return r.db.Model(&domain.EventHost{}).Where("id = ?", host.ID).Updates(updates).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Model(&domain.EventHost{}).Where("id = ?", host.ID).Updates(updates).Error
+}
+
+func (r *eventHostRepository) Delete(ctx context.Context, tx interface{}, id uuid.UUID) error {
+	if tx == nil {
+		return r.db.Delete(&domain.EventHost{}, "id = ?", id).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Delete(&domain.EventHost{}, "id = ?", id).Error
+}
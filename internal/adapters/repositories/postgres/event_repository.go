@@ -9,8 +9,12 @@ import (
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// hostedBy matches the events a restaurant created or co-hosts
+const hostedBy = "(restaurant_id = ? OR id IN (SELECT event_id FROM event_hosts WHERE restaurant_id = ?))"
+
 type eventRepository struct {
 	db *gorm.DB
 }
@@ -35,16 +39,43 @@ func (r *eventRepository) Create(ctx context.Context, tx interface{}, event *dom
 func (r *eventRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Event, error) {
 	var event domain.Event
 	if err := r.db.Where("id = ?", id).First(&event).Error; err != nil {
+		return nil, notFound(err, domain.ErrEventNotFound)
+	}
+
+	if err := r.db.Preload("Restaurant").Where("event_id = ?", id).Order("created_at ASC").Find(&event.Hosts).Error; err != nil {
 		return nil, err
 	}
+
 	return &event, nil
 }
 
-func (r *eventRepository) GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID, status string, limit, offset int) ([]*domain.Event, int, error) {
+func (r *eventRepository) GetByIDForUpdate(ctx context.Context, tx interface{}, id uuid.UUID) (*domain.Event, error) {
+	db := r.db
+	if tx != nil {
+		gormTx, ok := tx.(*gorm.DB)
+		if !ok {
+			return nil, fmt.Errorf("invalid transaction type")
+		}
+		db = gormTx
+	}
+
+	var event domain.Event
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&event).Error; err != nil {
+		return nil, notFound(err, domain.ErrEventNotFound)
+	}
+	return &event, nil
+}
+
+func (r *eventRepository) GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID, branchID *uuid.UUID, status string, limit, offset int) ([]*domain.Event, int, error) {
 	var events []*domain.Event
 	var count int64
 
-	query := r.db.Model(&domain.Event{}).Where("restaurant_id = ?", restaurantID)
+	// Co-hosted events count for every host
+	query := r.db.Model(&domain.Event{}).Where(hostedBy, restaurantID, restaurantID)
+
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	}
 
 	if status != "" {
 		query = query.Where("status = ?", status)
@@ -61,13 +92,19 @@ func (r *eventRepository) GetByRestaurantID(ctx context.Context, restaurantID uu
 	return events, int(count), nil
 }
 
-func (r *eventRepository) GetTodayEvents(ctx context.Context, restaurantID uuid.UUID) ([]*domain.Event, error) {
+func (r *eventRepository) GetTodayEvents(ctx context.Context, restaurantID uuid.UUID, branchID *uuid.UUID) ([]*domain.Event, error) {
 	var events []*domain.Event
 	today := time.Now().Format("2006-01-02")
 	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
 
-	if err := r.db.Where("restaurant_id = ? AND start_time >= ? AND start_time < ?",
-		restaurantID, today, tomorrow).Find(&events).Error; err != nil {
+	query := r.db.Where(hostedBy, restaurantID, restaurantID).
+		Where("start_time >= ? AND start_time < ?", today, tomorrow)
+
+	if branchID != nil {
+		query = query.Where("branch_id = ?", *branchID)
+	}
+
+	if err := query.Find(&events).Error; err != nil {
 		return nil, err
 	}
 
@@ -154,13 +191,41 @@ func (r *eventRepository) Delete(ctx context.Context, tx interface{}, id uuid.UU
 func (r *eventRepository) GetUpcomingEvents(ctx context.Context) ([]*domain.Event, error) {
 	var events []*domain.Event
 
-	// Get events that are upcoming and have not reached max volunteers
-	if err := r.db.Where("status = ?", domain.EventStatusUpcoming).
-		Where("start_time > ?", time.Now()).
-		Order("start_time asc").
+	// Only events of verified restaurants are shown publicly
+	if err := r.db.Joins("JOIN restaurants ON events.restaurant_id = restaurants.id AND restaurants.deleted_at IS NULL").
+		Where("restaurants.verification_status = ?", domain.VerificationStatusVerified).
+		Where("events.status = ?", domain.EventStatusUpcoming).
+		Where("events.start_time > ?", time.Now()).
+		Order("events.start_time asc").
 		Find(&events).Error; err != nil {
 		return nil, err
 	}
 
 	return events, nil
 }
+
+func (r *eventRepository) List(ctx context.Context, filter domain.ListFilter) ([]*domain.Event, int, error) {
+	var events []*domain.Event
+	var count int64
+
+	query := r.db.Model(&domain.Event{})
+
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		query = query.Where("title ILIKE ? OR location ILIKE ?", like, like)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Limit(filter.Limit).Offset(filter.Offset).Order("date DESC, start_time DESC").Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, int(count), nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type externalIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewExternalIdentityRepository(db *gorm.DB) ports.ExternalIdentityRepository {
+	return &externalIdentityRepository{db: db}
+}
+
+func (r *externalIdentityRepository) Create(ctx context.Context, tx interface{}, identity *domain.ExternalIdentity) error {
+	if tx == nil {
+		return r.db.Create(identity).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(identity).Error
+}
+
+func (r *externalIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error) {
+	var identity domain.ExternalIdentity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, notFound(err, domain.ErrIdentityNotFound)
+	}
+	return &identity, nil
+}
+
+func (r *externalIdentityRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.ExternalIdentity, error) {
+	var identities []*domain.ExternalIdentity
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (r *externalIdentityRepository) DeleteByUserID(ctx context.Context, tx interface{}, userID uuid.UUID) error {
+	if tx == nil {
+		return r.db.Where("user_id = ?", userID).Delete(&domain.ExternalIdentity{}).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Where("user_id = ?", userID).Delete(&domain.ExternalIdentity{}).Error
+}
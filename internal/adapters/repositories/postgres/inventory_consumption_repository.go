@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type inventoryConsumptionRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryConsumptionRepository(db *gorm.DB) ports.InventoryConsumptionRepository {
+	return &inventoryConsumptionRepository{db: db}
+}
+
+func (r *inventoryConsumptionRepository) Create(ctx context.Context, tx interface{}, consumption *domain.InventoryConsumption) error {
+	if tx == nil {
+		return r.db.Create(consumption).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(consumption).Error
+}
+
+func (r *inventoryConsumptionRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) ([]*domain.InventoryConsumption, error) {
+	var consumptions []*domain.InventoryConsumption
+	// Unscoped preload so usage of items deleted since the event still shows up
+	if err := r.db.Where("event_id = ?", eventID).
+		Preload("InventoryItem", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("created_at ASC").
+		Find(&consumptions).Error; err != nil {
+		return nil, err
+	}
+	return consumptions, nil
+}
+
+// GetByEventIDs loads the consumptions of several events in one query
+func (r *inventoryConsumptionRepository) GetByEventIDs(ctx context.Context, eventIDs []uuid.UUID) ([]*domain.InventoryConsumption, error) {
+	var consumptions []*domain.InventoryConsumption
+	if len(eventIDs) == 0 {
+		return consumptions, nil
+	}
+	if err := r.db.Where("event_id IN ?", eventIDs).
+		Preload("InventoryItem", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("created_at ASC").
+		Find(&consumptions).Error; err != nil {
+		return nil, err
+	}
+	return consumptions, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) ports.InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+func (r *inventoryRepository) Create(ctx context.Context, tx interface{}, item *domain.InventoryItem) error {
+	if tx == nil {
+		return r.db.Create(item).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(item).Error
+}
+
+func (r *inventoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.InventoryItem, error) {
+	var item domain.InventoryItem
+	if err := r.db.Where("id = ?", id).First(&item).Error; err != nil {
+		return nil, notFound(err, domain.ErrInventoryItemNotFound)
+	}
+	return &item, nil
+}
+
+func (r *inventoryRepository) GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.InventoryItem, error) {
+	var items []*domain.InventoryItem
+	if err := r.db.Where("restaurant_id = ?", restaurantID).
+		Order("expiry_date ASC NULLS LAST, name ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *inventoryRepository) GetExpiring(ctx context.Context, restaurantID uuid.UUID, before time.Time) ([]*domain.InventoryItem, error) {
+	var items []*domain.InventoryItem
+	if err := r.db.Where("restaurant_id = ? AND expiry_date IS NOT NULL AND expiry_date < ? AND quantity > 0", restaurantID, before).
+		Order("expiry_date ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *inventoryRepository) Update(ctx context.Context, tx interface{}, item *domain.InventoryItem) error {
+	if tx == nil {
+		return r.db.Save(item).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Save(item).Error
+}
+
+// DecrementQuantity takes quantity out of the stock of the item in a single statement, so
+// concurrent consumptions cannot overwrite each other. It reports false without changing
+// anything when less than quantity is left.
+func (r *inventoryRepository) DecrementQuantity(ctx context.Context, tx interface{}, id uuid.UUID, quantity float64) (bool, error) {
+	db := r.db
+	if tx != nil {
+		gormTx, ok := tx.(*gorm.DB)
+		if !ok {
+			return false, fmt.Errorf("invalid transaction type")
+		}
+		db = gormTx
+	}
+
+	result := db.Model(&domain.InventoryItem{}).
+		Where("id = ? AND quantity >= ?", id, quantity).
+		Update("quantity", gorm.Expr("quantity - ?", quantity))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *inventoryRepository) Delete(ctx context.Context, tx interface{}, id uuid.UUID) error {
+	if tx == nil {
+		return r.db.Delete(&domain.InventoryItem{}, "id = ?", id).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Delete(&domain.InventoryItem{}, "id = ?", id).Error
+}
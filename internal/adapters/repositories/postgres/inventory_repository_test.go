@@ -0,0 +1,97 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/repositories/postgres"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/application"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecrementQuantity(t *testing.T) {
+	db := openTestDB(t)
+	repo := postgres.NewInventoryRepository(db)
+	restaurant := seedRestaurant(t, db)
+
+	tests := []struct {
+		name     string
+		quantity float64
+		wantOK   bool
+		wantLeft float64
+	}{
+		{"part of the stock", 4, true, 6},
+		{"all of the stock", 10, true, 0},
+		{"more than the stock", 10.5, false, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			item := &domain.InventoryItem{RestaurantID: restaurant.ID, Name: "rice", Quantity: 10, Unit: "kg"}
+			require.NoError(t, repo.Create(ctx, nil, item))
+
+			ok, err := repo.DecrementQuantity(ctx, nil, item.ID, tt.quantity)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+
+			item, err = repo.GetByID(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLeft, item.Quantity)
+		})
+	}
+}
+
+func TestRecordConsumptionConcurrently(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	restaurant := seedRestaurant(t, db)
+	event := seedEvent(t, db, restaurant)
+
+	inventoryRepo := postgres.NewInventoryRepository(db)
+	consumptionRepo := postgres.NewInventoryConsumptionRepository(db)
+	service := application.NewInventoryService(postgres.NewTransactionManager(db), inventoryRepo, consumptionRepo, postgres.NewEventRepository(db))
+
+	item := &domain.InventoryItem{RestaurantID: restaurant.ID, Name: "dates", Quantity: 10, Unit: "kg"}
+	require.NoError(t, inventoryRepo.Create(ctx, nil, item))
+
+	// Every request passes the stock check before any of them takes its share
+	const requests = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.RecordConsumption(ctx, event.ID.String(), item.ID.String(), 1)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	recorded := 0
+	for err := range errs {
+		if err == nil {
+			recorded++
+			continue
+		}
+		var domainErr *domain.Error
+		require.True(t, errors.As(err, &domainErr), "unexpected error: %v", err)
+		assert.Equal(t, domain.ErrCodeInsufficientStock, domainErr.Code)
+	}
+	assert.Equal(t, 10, recorded)
+
+	item, err := inventoryRepo.GetByID(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Zero(t, item.Quantity)
+
+	consumptions, err := consumptionRepo.GetByEventIDs(ctx, []uuid.UUID{event.ID})
+	require.NoError(t, err)
+	assert.Len(t, consumptions, 10)
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) ports.LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, tx interface{}, attempt *domain.LoginAttempt) error {
+	if tx == nil {
+		return r.db.Create(attempt).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(attempt).Error
+}
+
+func (r *loginAttemptRepository) DeleteByUserID(ctx context.Context, tx interface{}, userID uuid.UUID) error {
+	if tx == nil {
+		return r.db.Where("user_id = ?", userID).Delete(&domain.LoginAttempt{}).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Where("user_id = ?", userID).Delete(&domain.LoginAttempt{}).Error
+}
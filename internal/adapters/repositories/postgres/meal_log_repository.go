@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type mealLogRepository struct {
+	db *gorm.DB
+}
+
+func NewMealLogRepository(db *gorm.DB) ports.MealLogRepository {
+	return &mealLogRepository{db: db}
+}
+
+// Create adds the entry, it fails with domain.ErrEntryAlreadyCorrected when the entry it
+// corrects was corrected already
+func (r *mealLogRepository) Create(ctx context.Context, tx interface{}, entry *domain.MealLogEntry) error {
+	if tx == nil {
+		return duplicate(r.db.Create(entry).Error, domain.ErrEntryAlreadyCorrected)
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return duplicate(gormTx.Create(entry).Error, domain.ErrEntryAlreadyCorrected)
+}
+
+func (r *mealLogRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MealLogEntry, error) {
+	var entry domain.MealLogEntry
+	if err := r.db.Where("id = ?", id).First(&entry).Error; err != nil {
+		return nil, notFound(err, domain.ErrMealLogEntryNotFound)
+	}
+	return &entry, nil
+}
+
+func (r *mealLogRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) ([]*domain.MealLogEntry, error) {
+	var entries []*domain.MealLogEntry
+	if err := r.db.Where("event_id = ?", eventID).Order("created_at ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *mealLogRepository) SumByEventID(ctx context.Context, tx interface{}, eventID uuid.UUID) (int, error) {
+	db := r.db
+	if tx != nil {
+		gormTx, ok := tx.(*gorm.DB)
+		if !ok {
+			return 0, fmt.Errorf("invalid transaction type")
+		}
+		db = gormTx
+	}
+
+	var total int64
+	if err := db.Model(&domain.MealLogEntry{}).
+		Where("event_id = ?", eventID).
+		Select("COALESCE(SUM(count), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return int(total), nil
+}
+
+func (r *mealLogRepository) SumByRestaurantID(ctx context.Context, tx interface{}, restaurantID uuid.UUID) (int, error) {
+	db := r.db
+	if tx != nil {
+		gormTx, ok := tx.(*gorm.DB)
+		if !ok {
+			return 0, fmt.Errorf("invalid transaction type")
+		}
+		db = gormTx
+	}
+
+	var total int64
+	if err := db.Model(&domain.MealLogEntry{}).
+		Where("restaurant_id = ?", restaurantID).
+		Select("COALESCE(SUM(count), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return int(total), nil
+}
+
+func (r *mealLogRepository) SumByEventAndRestaurantID(ctx context.Context, tx interface{}, eventID, restaurantID uuid.UUID) (int, error) {
+	db := r.db
+	if tx != nil {
+		gormTx, ok := tx.(*gorm.DB)
+		if !ok {
+			return 0, fmt.Errorf("invalid transaction type")
+		}
+		db = gormTx
+	}
+
+	var total int64
+	if err := db.Model(&domain.MealLogEntry{}).
+		Where("event_id = ? AND restaurant_id = ?", eventID, restaurantID).
+		Select("COALESCE(SUM(count), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return int(total), nil
+}
@@ -0,0 +1,150 @@
+package postgres_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/repositories/postgres"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/application"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newEventService(db *gorm.DB) ports.EventService {
+	return application.NewEventService(
+		postgres.NewTransactionManager(db),
+		postgres.NewEventRepository(db),
+		postgres.NewRestaurantRepository(db),
+		postgres.NewBranchRepository(db),
+		postgres.NewEventHostRepository(db),
+		postgres.NewMealLogRepository(db),
+		postgres.NewAuditLogRepository(db),
+	)
+}
+
+// concurrently runs fn n times at once and returns the errors
+func concurrently(n int, fn func(i int) error) []error {
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+// assertMealTotals checks the counters of the event and its restaurant against the log
+func assertMealTotals(t *testing.T, db *gorm.DB, event *domain.Event, want int) {
+	t.Helper()
+	ctx := context.Background()
+
+	sum, err := postgres.NewMealLogRepository(db).SumByEventID(ctx, nil, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, want, sum)
+
+	stored, err := postgres.NewEventRepository(db).GetByID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, want, stored.MealsServed)
+
+	restaurant, err := postgres.NewRestaurantRepository(db).GetByID(ctx, event.RestaurantID)
+	require.NoError(t, err)
+	assert.Equal(t, want, restaurant.MealsServed)
+}
+
+func TestMealLogEntryCorrectedOnce(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := postgres.NewMealLogRepository(db)
+	restaurant := seedRestaurant(t, db)
+	event := seedEvent(t, db, restaurant)
+
+	entry := &domain.MealLogEntry{EventID: event.ID, RestaurantID: restaurant.ID, RecordedBy: restaurant.UserID, Count: 10}
+	require.NoError(t, repo.Create(ctx, nil, entry))
+
+	correction := &domain.MealLogEntry{EventID: event.ID, RestaurantID: restaurant.ID, RecordedBy: restaurant.UserID, Count: -10, CorrectsEntryID: &entry.ID}
+	require.NoError(t, repo.Create(ctx, nil, correction))
+
+	again := &domain.MealLogEntry{EventID: event.ID, RestaurantID: restaurant.ID, RecordedBy: restaurant.UserID, Count: -10, CorrectsEntryID: &entry.ID}
+	assert.ErrorIs(t, repo.Create(ctx, nil, again), domain.ErrEntryAlreadyCorrected)
+}
+
+func TestRecordMealsConcurrently(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	service := newEventService(db)
+	restaurant := seedRestaurant(t, db)
+	event := seedEvent(t, db, restaurant)
+
+	errs := concurrently(20, func(int) error {
+		_, err := service.RecordMeals(ctx, event.ID.String(), restaurant.ID.String(), restaurant.UserID.String(), 5, "")
+		return err
+	})
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	assertMealTotals(t, db, event, 100)
+}
+
+func TestUpdateMealsServedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	service := newEventService(db)
+	restaurant := seedRestaurant(t, db)
+	event := seedEvent(t, db, restaurant)
+
+	// Each update logs the difference to the total it reads, which is only right when no
+	// other update changes that total in between
+	targets := []int{10, 20, 30, 40, 50, 60, 70, 80}
+	errs := concurrently(len(targets), func(i int) error {
+		return service.UpdateMealsServed(ctx, event.ID.String(), restaurant.ID.String(), restaurant.UserID.String(), targets[i])
+	})
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	stored, err := postgres.NewEventRepository(db).GetByID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Contains(t, targets, stored.MealsServed)
+	assertMealTotals(t, db, event, stored.MealsServed)
+}
+
+func TestCorrectMealEntryConcurrently(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	service := newEventService(db)
+	restaurant := seedRestaurant(t, db)
+	event := seedEvent(t, db, restaurant)
+
+	// The second entry keeps the host above zero, so only the unique correction stops the
+	// entry from being taken back twice
+	entry, err := service.RecordMeals(ctx, event.ID.String(), restaurant.ID.String(), restaurant.UserID.String(), 10, "")
+	require.NoError(t, err)
+	_, err = service.RecordMeals(ctx, event.ID.String(), restaurant.ID.String(), restaurant.UserID.String(), 10, "")
+	require.NoError(t, err)
+
+	errs := concurrently(5, func(int) error {
+		_, err := service.CorrectMealEntry(ctx, event.ID.String(), entry.ID.String(), restaurant.ID.String(), restaurant.UserID.String(), "counted twice")
+		return err
+	})
+
+	corrected := 0
+	for _, err := range errs {
+		if err == nil {
+			corrected++
+			continue
+		}
+		assert.ErrorIs(t, err, domain.ErrEntryAlreadyCorrected)
+	}
+	assert.Equal(t, 1, corrected)
+
+	assertMealTotals(t, db, event, 10)
+}
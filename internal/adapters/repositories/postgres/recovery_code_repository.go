@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) ports.RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+func (r *recoveryCodeRepository) ReplaceForUser(ctx context.Context, tx interface{}, userID uuid.UUID, codes []*domain.RecoveryCode) error {
+	db := r.db
+	if tx != nil {
+		gormTx, ok := tx.(*gorm.DB)
+		if !ok {
+			return fmt.Errorf("invalid transaction type")
+		}
+		db = gormTx
+	}
+
+	if err := db.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return db.Create(codes).Error
+}
+
+func (r *recoveryCodeRepository) Use(ctx context.Context, tx interface{}, userID uuid.UUID, codeHash string) (bool, error) {
+	db := r.db
+	if tx != nil {
+		gormTx, ok := tx.(*gorm.DB)
+		if !ok {
+			return false, fmt.Errorf("invalid transaction type")
+		}
+		db = gormTx
+	}
+
+	// The used_at condition makes concurrent uses of the same code succeed only once
+	result := db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *recoveryCodeRepository) DeleteByUserID(ctx context.Context, tx interface{}, userID uuid.UUID) error {
+	if tx == nil {
+		return r.db.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+}
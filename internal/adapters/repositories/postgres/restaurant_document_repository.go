@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type restaurantDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewRestaurantDocumentRepository(db *gorm.DB) ports.RestaurantDocumentRepository {
+	return &restaurantDocumentRepository{db: db}
+}
+
+func (r *restaurantDocumentRepository) Create(ctx context.Context, tx interface{}, document *domain.RestaurantDocument) error {
+	if tx == nil {
+		return r.db.Create(document).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(document).Error
+}
+
+func (r *restaurantDocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RestaurantDocument, error) {
+	var document domain.RestaurantDocument
+	if err := r.db.Where("id = ?", id).First(&document).Error; err != nil {
+		return nil, notFound(err, domain.ErrDocumentNotFound)
+	}
+	return &document, nil
+}
+
+func (r *restaurantDocumentRepository) GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.RestaurantDocument, error) {
+	var documents []*domain.RestaurantDocument
+	if err := r.db.Where("restaurant_id = ?", restaurantID).Order("uploaded_at DESC").Find(&documents).Error; err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (r *restaurantDocumentRepository) Delete(ctx context.Context, tx interface{}, id uuid.UUID) error {
+	if tx == nil {
+		return r.db.Delete(&domain.RestaurantDocument{}, "id = ?", id).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Delete(&domain.RestaurantDocument{}, "id = ?", id).Error
+}
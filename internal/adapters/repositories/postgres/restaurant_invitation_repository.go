@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type restaurantInvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewRestaurantInvitationRepository(db *gorm.DB) ports.RestaurantInvitationRepository {
+	return &restaurantInvitationRepository{db: db}
+}
+
+func (r *restaurantInvitationRepository) Create(ctx context.Context, tx interface{}, invitation *domain.RestaurantInvitation) error {
+	if tx == nil {
+		return r.db.Create(invitation).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(invitation).Error
+}
+
+func (r *restaurantInvitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RestaurantInvitation, error) {
+	var invitation domain.RestaurantInvitation
+	if err := r.db.Where("id = ?", id).First(&invitation).Error; err != nil {
+		return nil, notFound(err, domain.ErrInvitationNotFound)
+	}
+	return &invitation, nil
+}
+
+func (r *restaurantInvitationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.RestaurantInvitation, error) {
+	var invitation domain.RestaurantInvitation
+	if err := r.db.Preload("Restaurant").Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		return nil, notFound(err, domain.ErrInvitationNotFound)
+	}
+	return &invitation, nil
+}
+
+func (r *restaurantInvitationRepository) GetPendingByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.RestaurantInvitation, error) {
+	var invitations []*domain.RestaurantInvitation
+	if err := r.db.Where("restaurant_id = ?", restaurantID).
+		Where("accepted_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *restaurantInvitationRepository) MarkAccepted(ctx context.Context, tx interface{}, id uuid.UUID) error {
+	if tx == nil {
+		return r.db.Model(&domain.RestaurantInvitation{}).
+			Where("id = ?", id).
+			Update("accepted_at", time.Now()).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Model(&domain.RestaurantInvitation{}).
+		Where("id = ?", id).
+		Update("accepted_at", time.Now()).Error
+}
+
+func (r *restaurantInvitationRepository) Delete(ctx context.Context, tx interface{}, id uuid.UUID) error {
+	if tx == nil {
+		return r.db.Delete(&domain.RestaurantInvitation{}, "id = ?", id).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Delete(&domain.RestaurantInvitation{}, "id = ?", id).Error
+}
+
+func (r *restaurantInvitationRepository) DeletePending(ctx context.Context, tx interface{}, restaurantID uuid.UUID, email string) error {
+	if tx == nil {
+		return r.db.Where("restaurant_id = ? AND email = ? AND accepted_at IS NULL", restaurantID, email).
+			Delete(&domain.RestaurantInvitation{}).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Where("restaurant_id = ? AND email = ? AND accepted_at IS NULL", restaurantID, email).
+		Delete(&domain.RestaurantInvitation{}).Error
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type restaurantMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewRestaurantMemberRepository(db *gorm.DB) ports.RestaurantMemberRepository {
+	return &restaurantMemberRepository{db: db}
+}
+
+func (r *restaurantMemberRepository) Create(ctx context.Context, tx interface{}, member *domain.RestaurantMember) error {
+	if tx == nil {
+		return r.db.Create(member).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Create(member).Error
+}
+
+func (r *restaurantMemberRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RestaurantMember, error) {
+	var member domain.RestaurantMember
+	if err := r.db.Preload("User").Where("id = ?", id).First(&member).Error; err != nil {
+		return nil, notFound(err, domain.ErrMemberNotFound)
+	}
+	return &member, nil
+}
+
+func (r *restaurantMemberRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.RestaurantMember, error) {
+	var members []*domain.RestaurantMember
+	if err := r.db.Preload("Restaurant").
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *restaurantMemberRepository) GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.RestaurantMember, error) {
+	var members []*domain.RestaurantMember
+	if err := r.db.Preload("User").
+		Where("restaurant_id = ?", restaurantID).
+		Order("created_at ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *restaurantMemberRepository) GetByRestaurantAndUser(ctx context.Context, restaurantID, userID uuid.UUID) (*domain.RestaurantMember, error) {
+	var member domain.RestaurantMember
+	if err := r.db.Preload("Restaurant").
+		Where("restaurant_id = ? AND user_id = ?", restaurantID, userID).
+		First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *restaurantMemberRepository) UpdateAccess(ctx context.Context, tx interface{}, id uuid.UUID, role domain.MembershipRole, branchID *uuid.UUID) error {
+	updates := map[string]interface{}{
+		"role":      role,
+		"branch_id": branchID,
+	}
+
+	if tx == nil {
+		return r.db.Model(&domain.RestaurantMember{}).
+			Where("id = ?", id).
+			Updates(updates).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Model(&domain.RestaurantMember{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (r *restaurantMemberRepository) Delete(ctx context.Context, tx interface{}, id uuid.UUID) error {
+	if tx == nil {
+		return r.db.Delete(&domain.RestaurantMember{}, "id = ?", id).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Delete(&domain.RestaurantMember{}, "id = ?", id).Error
+}
+
+func (r *restaurantMemberRepository) DeleteByUserID(ctx context.Context, tx interface{}, userID uuid.UUID) error {
+	if tx == nil {
+		return r.db.Unscoped().Where("user_id = ?", userID).Delete(&domain.RestaurantMember{}).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Unscoped().Where("user_id = ?", userID).Delete(&domain.RestaurantMember{}).Error
+}
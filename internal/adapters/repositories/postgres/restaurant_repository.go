@@ -3,11 +3,13 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type restaurantRepository struct {
@@ -34,15 +36,36 @@ func (r *restaurantRepository) Create(ctx context.Context, tx interface{}, resta
 func (r *restaurantRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Restaurant, error) {
 	var restaurant domain.Restaurant
 	if err := r.db.Where("id = ?", id).First(&restaurant).Error; err != nil {
-		return nil, err
+		return nil, notFound(err, domain.ErrRestaurantNotFound)
 	}
 	return &restaurant, nil
 }
 
+func (r *restaurantRepository) GetByIDForUpdate(ctx context.Context, tx interface{}, id uuid.UUID) (*domain.Restaurant, error) {
+	db := r.db
+	if tx != nil {
+		gormTx, ok := tx.(*gorm.DB)
+		if !ok {
+			return nil, fmt.Errorf("invalid transaction type")
+		}
+		db = gormTx
+	}
+
+	var restaurant domain.Restaurant
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&restaurant).Error; err != nil {
+		return nil, notFound(err, domain.ErrRestaurantNotFound)
+	}
+	return &restaurant, nil
+}
+
+// GetByUserID returns the restaurant the user is a member of, preferring one they own
 func (r *restaurantRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.Restaurant, error) {
 	var restaurant domain.Restaurant
-	if err := r.db.Where("user_id = ?", userID).First(&restaurant).Error; err != nil {
-		return nil, err
+	if err := r.db.Joins("JOIN restaurant_members ON restaurant_members.restaurant_id = restaurants.id").
+		Where("restaurant_members.user_id = ?", userID).
+		Order("restaurant_members.role = 'owner' DESC, restaurant_members.created_at ASC").
+		First(&restaurant).Error; err != nil {
+		return nil, notFound(err, domain.ErrNoMembership)
 	}
 	return &restaurant, nil
 }
@@ -95,3 +118,60 @@ func (r *restaurantRepository) Delete(ctx context.Context, tx interface{}, id uu
 
 	return gormTx.Delete(&domain.Restaurant{}, "id = ?", id).Error
 }
+
+func (r *restaurantRepository) List(ctx context.Context, filter domain.ListFilter) ([]*domain.Restaurant, int, error) {
+	var restaurants []*domain.Restaurant
+	var count int64
+
+	query := r.db.Model(&domain.Restaurant{})
+
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		query = query.Where("name ILIKE ? OR address ILIKE ?", like, like)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("verification_status = ?", filter.Status)
+	}
+
+	if filter.Type != "" {
+		query = query.Where("organization_type = ?", filter.Type)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Limit(filter.Limit).Offset(filter.Offset).Order("created_at DESC").Find(&restaurants).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return restaurants, int(count), nil
+}
+
+func (r *restaurantRepository) UpdateVerification(ctx context.Context, tx interface{}, id uuid.UUID, status domain.VerificationStatus, reason string) error {
+	updates := map[string]interface{}{
+		"verification_status": status,
+		"rejection_reason":    reason,
+		"verified_at":         nil,
+	}
+
+	if status == domain.VerificationStatusVerified {
+		updates["verified_at"] = time.Now()
+	}
+
+	if tx == nil {
+		return r.db.Model(&domain.Restaurant{}).
+			Where("id = ?", id).
+			Updates(updates).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Model(&domain.Restaurant{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+// The policy is a single row with this ID
+const securityPolicyID = 1
+
+type securityPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewSecurityPolicyRepository(db *gorm.DB) ports.SecurityPolicyRepository {
+	return &securityPolicyRepository{db: db}
+}
+
+func (r *securityPolicyRepository) Get(ctx context.Context) (*domain.SecurityPolicy, error) {
+	var policy domain.SecurityPolicy
+	err := r.db.First(&policy, securityPolicyID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &domain.SecurityPolicy{ID: securityPolicyID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *securityPolicyRepository) Save(ctx context.Context, tx interface{}, policy *domain.SecurityPolicy) error {
+	policy.ID = securityPolicyID
+
+	if tx == nil {
+		return r.db.Save(policy).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Save(policy).Error
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
@@ -37,7 +38,7 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 	var user domain.User
 	err := r.db.Where("id = ?", id).First(&user).Error
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, domain.ErrUserNotFound)
 	}
 	return &user, nil
 }
@@ -46,7 +47,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	var user domain.User
 	err := r.db.Where("email = ?", email).First(&user).Error
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, domain.ErrUserNotFound)
 	}
 	return &user, nil
 }
@@ -55,7 +56,7 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*d
 	var user domain.User
 	err := r.db.Where("username = ?", username).First(&user).Error
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, domain.ErrUserNotFound)
 	}
 	return &user, nil
 }
@@ -85,3 +86,58 @@ func (r *userRepository) Delete(ctx context.Context, tx interface{}, id uuid.UUI
 
 	return gormTx.Delete(&domain.User{}, "id = ?", id).Error
 }
+
+// Purge removes the user for good, Delete only hides it
+func (r *userRepository) Purge(ctx context.Context, tx interface{}, id uuid.UUID) error {
+	if tx == nil {
+		return r.db.Unscoped().Delete(&domain.User{}, "id = ?", id).Error
+	}
+
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return fmt.Errorf("invalid transaction type")
+	}
+
+	return gormTx.Unscoped().Delete(&domain.User{}, "id = ?", id).Error
+}
+
+func (r *userRepository) GetDueForDeletion(ctx context.Context, before time.Time) ([]*domain.User, error) {
+	var users []*domain.User
+	if err := r.db.Where("deletion_due_at IS NOT NULL AND deletion_due_at <= ?", before).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *userRepository) List(ctx context.Context, filter domain.ListFilter) ([]*domain.User, int, error) {
+	var users []*domain.User
+	var count int64
+
+	query := r.db.Model(&domain.User{})
+
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		query = query.Where("username ILIKE ? OR email ILIKE ?", like, like)
+	}
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	switch filter.Status {
+	case "suspended":
+		query = query.Where("suspended_at IS NOT NULL")
+	case "active":
+		query = query.Where("suspended_at IS NULL")
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Limit(filter.Limit).Offset(filter.Offset).Order("created_at DESC").Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, int(count), nil
+}
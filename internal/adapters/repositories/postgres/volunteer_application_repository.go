@@ -34,7 +34,7 @@ func (r *volunteerApplicationRepository) Create(ctx context.Context, tx interfac
 func (r *volunteerApplicationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.VolunteerApplication, error) {
 	var app domain.VolunteerApplication
 	if err := r.db.Where("id = ?", id).First(&app).Error; err != nil {
-		return nil, err
+		return nil, notFound(err, domain.ErrApplicationNotFound)
 	}
 	return &app, nil
 }
@@ -57,8 +57,9 @@ func (r *volunteerApplicationRepository) GetByEventID(ctx context.Context, event
 func (r *volunteerApplicationRepository) GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID, status string) ([]*domain.VolunteerApplication, error) {
 	var apps []*domain.VolunteerApplication
 
+	// Co-hosts only see the applications they were allowed to review
 	query := r.db.Joins("JOIN events ON volunteer_applications.event_id = events.id").
-		Where("events.restaurant_id = ?", restaurantID)
+		Where("(events.restaurant_id = ? OR events.id IN (SELECT event_id FROM event_hosts WHERE restaurant_id = ? AND can_review_applications))", restaurantID, restaurantID)
 
 	if status != "" {
 		query = query.Where("volunteer_applications.status = ?", status)
@@ -108,3 +109,31 @@ func (r *volunteerApplicationRepository) Delete(ctx context.Context, tx interfac
 
 	return gormTx.Delete(&domain.VolunteerApplication{}, "id = ?", id).Error
 }
+
+func (r *volunteerApplicationRepository) List(ctx context.Context, filter domain.ListFilter) ([]*domain.VolunteerApplication, int, error) {
+	var apps []*domain.VolunteerApplication
+	var count int64
+
+	query := r.db.Model(&domain.VolunteerApplication{})
+
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		query = query.Where("role ILIKE ?", like)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Volunteer").Preload("Event").
+		Limit(filter.Limit).Offset(filter.Offset).Order("applied_at DESC").
+		Find(&apps).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return apps, int(count), nil
+}
@@ -34,7 +34,7 @@ func (r *volunteerRepository) Create(ctx context.Context, tx interface{}, volunt
 func (r *volunteerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Volunteer, error) {
 	var volunteer domain.Volunteer
 	if err := r.db.Where("id = ?", id).First(&volunteer).Error; err != nil {
-		return nil, err
+		return nil, notFound(err, domain.ErrVolunteerNotFound)
 	}
 	return &volunteer, nil
 }
@@ -42,7 +42,7 @@ func (r *volunteerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domai
 func (r *volunteerRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.Volunteer, error) {
 	var volunteer domain.Volunteer
 	if err := r.db.Where("user_id = ?", userID).First(&volunteer).Error; err != nil {
-		return nil, err
+		return nil, notFound(err, domain.ErrVolunteerNotFound)
 	}
 	return &volunteer, nil
 }
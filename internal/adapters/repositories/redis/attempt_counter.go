@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/go-redis/redis/v8"
+)
+
+type attemptCounter struct {
+	conn *Connection
+}
+
+func NewAttemptCounter(conn *Connection) ports.AttemptCounter {
+	return &attemptCounter{
+		conn: conn,
+	}
+}
+
+// Attempts are kept in a sorted set scored by their time, so the window slides instead of
+// resetting at fixed intervals
+
+func (c *attemptCounter) Record(ctx context.Context, key string, window time.Duration) error {
+	now := time.Now()
+	redisKey := fmt.Sprintf("attempts:%s", key)
+
+	pipe := c.conn.Client.TxPipeline()
+	pipe.ZAdd(ctx, redisKey, &redis.Z{Score: float64(now.UnixNano()), Member: now.UnixNano()})
+	pipe.ZRemRangeByScore(ctx, redisKey, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+	pipe.Expire(ctx, redisKey, window)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *attemptCounter) Recent(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	redisKey := fmt.Sprintf("attempts:%s", key)
+	min := strconv.FormatInt(time.Now().Add(-window).UnixNano(), 10)
+
+	count, err := c.conn.Client.ZCount(ctx, redisKey, min, "+inf").Result()
+	if err != nil || count == 0 {
+		return 0, time.Time{}, err
+	}
+
+	latest, err := c.conn.Client.ZRevRangeWithScores(ctx, redisKey, 0, 0).Result()
+	if err != nil || len(latest) == 0 {
+		return int(count), time.Time{}, err
+	}
+
+	return int(count), time.Unix(0, int64(latest[0].Score)), nil
+}
+
+func (c *attemptCounter) Reset(ctx context.Context, key string) error {
+	return c.conn.Client.Del(ctx, fmt.Sprintf("attempts:%s", key)).Err()
+}
@@ -0,0 +1,84 @@
+package redis_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/repositories/redis"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestRedis connects to the Redis at TEST_REDIS_ADDR, the tests are skipped without one
+func openTestRedis(t *testing.T) *redis.Connection {
+	t.Helper()
+
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR not set")
+	}
+
+	conn, err := redis.NewConnection(&config.Config{Redis: config.RedisConfig{
+		Address:  addr,
+		Password: os.Getenv("TEST_REDIS_PASSWORD"),
+	}})
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	return conn
+}
+
+func TestAttemptCounterSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	counter := redis.NewAttemptCounter(openTestRedis(t))
+	key := "test:" + uuid.NewString()
+	window := time.Second
+
+	require.NoError(t, counter.Record(ctx, key, window))
+	require.NoError(t, counter.Record(ctx, key, window))
+
+	count, latest, err := counter.Recent(ctx, key, window)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.WithinDuration(t, time.Now(), latest, 100*time.Millisecond)
+
+	time.Sleep(600 * time.Millisecond)
+	require.NoError(t, counter.Record(ctx, key, window))
+	last := time.Now()
+
+	// The first two leave the window one by one instead of the count resetting at once
+	time.Sleep(600 * time.Millisecond)
+	count, latest, err = counter.Recent(ctx, key, window)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.WithinDuration(t, last, latest, 100*time.Millisecond)
+
+	time.Sleep(600 * time.Millisecond)
+	count, latest, err = counter.Recent(ctx, key, window)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.True(t, latest.IsZero())
+}
+
+func TestAttemptCounterReset(t *testing.T) {
+	ctx := context.Background()
+	counter := redis.NewAttemptCounter(openTestRedis(t))
+	key := "test:" + uuid.NewString()
+	other := "test:" + uuid.NewString()
+
+	require.NoError(t, counter.Record(ctx, key, time.Minute))
+	require.NoError(t, counter.Record(ctx, other, time.Minute))
+	require.NoError(t, counter.Reset(ctx, key))
+
+	count, _, err := counter.Recent(ctx, key, time.Minute)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	count, _, err = counter.Recent(ctx, other, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "other keys are kept")
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+type oneTimeTokenStore struct {
+	conn *Connection
+}
+
+func NewOneTimeTokenStore(conn *Connection) ports.OneTimeTokenStore {
+	return &oneTimeTokenStore{
+		conn: conn,
+	}
+}
+
+func (s *oneTimeTokenStore) Store(ctx context.Context, purpose string, tokenHash string, subjectID uuid.UUID, expiration time.Duration) error {
+	userKey := fmt.Sprintf("ott:%s:user:%s", purpose, subjectID.String())
+
+	// A new token replaces the one sent before
+	previous, err := s.conn.Client.GetSet(ctx, userKey, tokenHash).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	if previous != "" {
+		if err := s.conn.Client.Del(ctx, fmt.Sprintf("ott:%s:%s", purpose, previous)).Err(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.conn.Client.Expire(ctx, userKey, expiration).Err(); err != nil {
+		return err
+	}
+
+	tokenKey := fmt.Sprintf("ott:%s:%s", purpose, tokenHash)
+	return s.conn.Client.Set(ctx, tokenKey, subjectID.String(), expiration).Err()
+}
+
+func (s *oneTimeTokenStore) Consume(ctx context.Context, purpose string, tokenHash string) (uuid.UUID, error) {
+	// GETDEL makes sure the token can only be used once, even by concurrent requests
+	value, err := s.conn.Client.GetDel(ctx, fmt.Sprintf("ott:%s:%s", purpose, tokenHash)).Result()
+	if err != nil {
+		return uuid.Nil, domain.ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, domain.ErrInvalidToken
+	}
+
+	_ = s.conn.Client.Del(ctx, fmt.Sprintf("ott:%s:user:%s", purpose, userID.String())).Err()
+
+	return userID, nil
+}
@@ -0,0 +1,24 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+)
+
+type throttle struct {
+	conn *Connection
+}
+
+func NewThrottle(conn *Connection) ports.Throttle {
+	return &throttle{
+		conn: conn,
+	}
+}
+
+func (t *throttle) Allow(ctx context.Context, key string, interval time.Duration) (bool, error) {
+	// SETNX only succeeds for the first caller until the key expires
+	return t.conn.Client.SetNX(ctx, fmt.Sprintf("throttle:%s", key), 1, interval).Result()
+}
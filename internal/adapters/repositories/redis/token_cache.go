@@ -2,10 +2,15 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 )
 
@@ -19,50 +24,184 @@ func NewTokenCache(conn *Connection) ports.TokenCache {
 	}
 }
 
-func (c *tokenCache) StoreToken(ctx context.Context, userID uuid.UUID, token string, expiration time.Duration) error {
-	// Store by user ID
-	userKey := fmt.Sprintf("user:%s:token", userID.String())
-	if err := c.conn.Client.Set(ctx, userKey, token, expiration).Err(); err != nil {
+func sessionKey(sessionID uuid.UUID) string {
+	return fmt.Sprintf("session:%s", sessionID.String())
+}
+
+// The refresh token digest has its own key so touching a session cannot undo a rotation
+func refreshTokenKey(sessionID uuid.UUID) string {
+	return fmt.Sprintf("session:%s:refresh", sessionID.String())
+}
+
+func rotatedTokensKey(sessionID uuid.UUID) string {
+	return fmt.Sprintf("session:%s:rotated", sessionID.String())
+}
+
+func userSessionsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user:%s:sessions", userID.String())
+}
+
+// rotateRefreshTokenScript swaps the refresh token digest of KEYS[1] from ARGV[1] to ARGV[2]
+// and files the old one under the rotated digests of KEYS[2]. The session itself in KEYS[3]
+// is replaced by ARGV[3], everything expires after ARGV[4] milliseconds. Nothing changes
+// unless the digest is still ARGV[1], a missing session included.
+var rotateRefreshTokenScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[4])
+redis.call("SADD", KEYS[2], ARGV[1])
+redis.call("PEXPIRE", KEYS[2], ARGV[4])
+redis.call("SET", KEYS[3], ARGV[3], "PX", ARGV[4])
+return 1
+`)
+
+func (c *tokenCache) StoreSession(ctx context.Context, session *domain.Session, expiration time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	if err := c.conn.Client.Set(ctx, sessionKey(session.ID), data, expiration).Err(); err != nil {
+		return err
+	}
+	if err := c.conn.Client.Set(ctx, refreshTokenKey(session.ID), session.RefreshTokenHash, expiration).Err(); err != nil {
 		return err
 	}
+	return c.indexSession(ctx, session, expiration)
+}
 
-	// Store by token for validation
-	tokenKey := fmt.Sprintf("token:%s", token)
-	return c.conn.Client.Set(ctx, tokenKey, userID.String(), expiration).Err()
+// indexSession lists the session under its user, the index lives as long as the
+// longest-lived session
+func (c *tokenCache) indexSession(ctx context.Context, session *domain.Session, expiration time.Duration) error {
+	userKey := userSessionsKey(session.UserID)
+	if err := c.conn.Client.SAdd(ctx, userKey, session.ID.String()).Err(); err != nil {
+		return err
+	}
+	ttl, err := c.conn.Client.TTL(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+	if ttl >= expiration {
+		return nil
+	}
+	return c.conn.Client.Expire(ctx, userKey, expiration).Err()
 }
 
-func (c *tokenCache) GetToken(ctx context.Context, userID uuid.UUID) (string, error) {
-	key := fmt.Sprintf("user:%s:token", userID.String())
-	token, err := c.conn.Client.Get(ctx, key).Result()
+func (c *tokenCache) GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error) {
+	data, err := c.conn.Client.Get(ctx, sessionKey(sessionID)).Bytes()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return token, nil
+
+	var session domain.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+
+	session.RefreshTokenHash, err = c.conn.Client.Get(ctx, refreshTokenKey(sessionID)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	session.RotatedTokenHashes, err = c.conn.Client.SMembers(ctx, rotatedTokensKey(sessionID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
 }
 
-func (c *tokenCache) InvalidateToken(ctx context.Context, userID uuid.UUID) error {
-	// Get the token first
-	token, err := c.GetToken(ctx, userID)
+func (c *tokenCache) RotateRefreshToken(ctx context.Context, session *domain.Session, previousHash string, expiration time.Duration) error {
+	data, err := json.Marshal(session)
 	if err != nil {
 		return err
 	}
 
-	// Delete by user ID
-	userKey := fmt.Sprintf("user:%s:token", userID.String())
-	if err := c.conn.Client.Del(ctx, userKey).Err(); err != nil {
+	keys := []string{refreshTokenKey(session.ID), rotatedTokensKey(session.ID), sessionKey(session.ID)}
+	swapped, err := rotateRefreshTokenScript.Run(ctx, c.conn.Client, keys,
+		previousHash, session.RefreshTokenHash, data, expiration.Milliseconds()).Int()
+	if err != nil {
 		return err
 	}
+	if swapped == 0 {
+		return domain.ErrRefreshTokenReused
+	}
 
-	// Delete by token
-	tokenKey := fmt.Sprintf("token:%s", token)
-	return c.conn.Client.Del(ctx, tokenKey).Err()
+	return c.indexSession(ctx, session, expiration)
 }
 
-func (c *tokenCache) TokenExists(ctx context.Context, token string) (bool, error) {
-	key := fmt.Sprintf("token:%s", token)
-	exists, err := c.conn.Client.Exists(ctx, key).Result()
+func (c *tokenCache) TouchSession(ctx context.Context, sessionID uuid.UUID, seenAt time.Time) error {
+	session, err := c.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	session.LastSeenAt = seenAt
+
+	data, err := json.Marshal(session)
 	if err != nil {
-		return false, err
+		return err
+	}
+
+	// Only while the session still exists, a revocation in the meantime must not bring it
+	// back without an expiry
+	return c.conn.Client.SetXX(ctx, sessionKey(sessionID), data, redis.KeepTTL).Err()
+}
+
+func (c *tokenCache) ListSessions(ctx context.Context, userID uuid.UUID) ([]*domain.Session, error) {
+	ids, err := c.conn.Client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*domain.Session, 0, len(ids))
+	for _, id := range ids {
+		sessionID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+
+		session, err := c.GetSession(ctx, sessionID)
+		if errors.Is(err, redis.Nil) {
+			// The session expired, drop it from the index
+			_ = c.conn.Client.SRem(ctx, userSessionsKey(userID), id).Err()
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
 	}
-	return exists > 0, nil
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+func (c *tokenCache) InvalidateSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	if err := c.conn.Client.Del(ctx, sessionKey(sessionID), refreshTokenKey(sessionID), rotatedTokensKey(sessionID)).Err(); err != nil {
+		return err
+	}
+	return c.conn.Client.SRem(ctx, userSessionsKey(userID), sessionID.String()).Err()
+}
+
+func (c *tokenCache) InvalidateUserSessions(ctx context.Context, userID uuid.UUID) error {
+	userKey := userSessionsKey(userID)
+	ids, err := c.conn.Client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userKey}
+	for _, id := range ids {
+		sessionID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, sessionKey(sessionID), refreshTokenKey(sessionID), rotatedTokensKey(sessionID))
+	}
+
+	return c.conn.Client.Del(ctx, keys...).Err()
 }
@@ -0,0 +1,42 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/repositories/redis"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCacheRotateRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	cache := redis.NewTokenCache(openTestRedis(t))
+
+	session := &domain.Session{ID: uuid.New(), UserID: uuid.New(), RefreshTokenHash: "first"}
+	require.NoError(t, cache.StoreSession(ctx, session, time.Minute))
+	t.Cleanup(func() { _ = cache.InvalidateUserSessions(ctx, session.UserID) })
+
+	rotated := *session
+	rotated.RefreshTokenHash = "second"
+	require.NoError(t, cache.RotateRefreshToken(ctx, &rotated, "first", time.Minute))
+
+	// A second rotation from the same token lost the race and changes nothing
+	lost := *session
+	lost.RefreshTokenHash = "third"
+	assert.ErrorIs(t, cache.RotateRefreshToken(ctx, &lost, "first", time.Minute), domain.ErrRefreshTokenReused)
+
+	stored, err := cache.GetSession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "second", stored.RefreshTokenHash)
+	assert.Equal(t, []string{"first"}, stored.RotatedTokenHashes)
+
+	// A revoked session is not brought back
+	require.NoError(t, cache.InvalidateSession(ctx, session.UserID, session.ID))
+	assert.ErrorIs(t, cache.RotateRefreshToken(ctx, &lost, "second", time.Minute), domain.ErrRefreshTokenReused)
+	_, err = cache.GetSession(ctx, session.ID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/adapters/config"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+)
+
+type fileStorage struct {
+	basePath string
+}
+
+func NewFileStorage(cfg *config.Config) (ports.FileStorage, error) {
+	basePath, err := filepath.Abs(cfg.Storage.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage path: %w", err)
+	}
+
+	if err := os.MkdirAll(basePath, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &fileStorage{basePath: basePath}, nil
+}
+
+func (s *fileStorage) Save(ctx context.Context, key string, content io.Reader) error {
+	path, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial upload
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *fileStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (s *fileStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// resolve maps a key to a path inside the base directory, rejecting keys that escape it
+func (s *fileStorage) resolve(key string) (string, error) {
+	path := filepath.Join(s.basePath, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, s.basePath+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage key: %s", key)
+	}
+	return path, nil
+}
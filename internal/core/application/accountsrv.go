@@ -0,0 +1,433 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	password_util "github.com/SOU9OUR-DCF/dcf-backend.git/pkg/password"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/token"
+)
+
+const (
+	passwordResetPurpose = "password_reset"
+	passwordResetTTL     = time.Hour
+
+	emailVerificationPurpose         = "email_verification"
+	businessEmailVerificationPurpose = "business_email_verification"
+	emailVerificationTTL             = 48 * time.Hour
+	verificationResendInterval       = time.Minute
+
+	emailChangePurpose = "email_change"
+	emailChangeTTL     = 24 * time.Hour
+)
+
+type accountService struct {
+	txManager      ports.TransactionManager
+	userRepo       ports.UserRepository
+	restaurantRepo ports.RestaurantRepository
+	auditRepo      ports.AuditLogRepository
+	tokenCache     ports.TokenCache
+	tokenStore     ports.OneTimeTokenStore
+	throttle       ports.Throttle
+	mailer         ports.Mailer
+	appURL         string
+}
+
+func NewAccountService(
+	txManager ports.TransactionManager,
+	userRepo ports.UserRepository,
+	restaurantRepo ports.RestaurantRepository,
+	auditRepo ports.AuditLogRepository,
+	tokenCache ports.TokenCache,
+	tokenStore ports.OneTimeTokenStore,
+	throttle ports.Throttle,
+	mailer ports.Mailer,
+	appURL string,
+) ports.AccountService {
+	return &accountService{
+		txManager:      txManager,
+		userRepo:       userRepo,
+		restaurantRepo: restaurantRepo,
+		auditRepo:      auditRepo,
+		tokenCache:     tokenCache,
+		tokenStore:     tokenStore,
+		throttle:       throttle,
+		mailer:         mailer,
+		appURL:         strings.TrimRight(appURL, "/"),
+	}
+}
+
+// RequestPasswordReset emails a single-use reset link to the account with this address.
+// Unknown addresses are ignored silently so the endpoint cannot be used to find accounts.
+func (s *accountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || user.IsSuspended() {
+		return nil
+	}
+
+	rawToken, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokenStore.Store(ctx, passwordResetPurpose, token.Hash(rawToken), user.ID, passwordResetTTL); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.appURL, rawToken)
+	body := fmt.Sprintf(
+		"Hello %s,\n\nWe received a request to reset your password. Choose a new one here: %s\n\nThe link expires in one hour. If you did not ask for a new password you can ignore this email.",
+		user.Username, link,
+	)
+	return s.mailer.Send(ctx, user.Email, "Reset your password", body)
+}
+
+// ResetPassword sets a new password with a token from a reset email and signs the user
+// out everywhere
+func (s *accountService) ResetPassword(ctx context.Context, rawToken string, newPassword string) error {
+	userID, err := s.tokenStore.Consume(ctx, passwordResetPurpose, token.Hash(rawToken))
+	if err != nil {
+		return domain.ErrInvalidResetLink
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.ErrInvalidResetLink
+	}
+
+	hashedPassword, err := password_util.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+		return auditAccount(ctx, s.auditRepo, tx, domain.AuditActionPasswordReset, user.ID, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sessions opened with the old password must not outlive it
+	_ = s.tokenCache.InvalidateUserSessions(ctx, user.ID)
+
+	return nil
+}
+
+// SendVerificationEmail emails the user a new link to verify their email address
+func (s *accountService) SendVerificationEmail(ctx context.Context, userID string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	if err := s.throttleResend(ctx, "email_verification:"+user.ID.String()); err != nil {
+		return err
+	}
+
+	return s.sendEmailVerification(ctx, user)
+}
+
+// VerifyEmail marks the email address of the user a verification link was sent to as verified
+func (s *accountService) VerifyEmail(ctx context.Context, rawToken string) error {
+	userID, err := s.tokenStore.Consume(ctx, emailVerificationPurpose, token.Hash(rawToken))
+	if err != nil {
+		return domain.ErrInvalidVerificationLink
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.ErrInvalidVerificationLink
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.userRepo.Update(ctx, tx, user)
+	})
+}
+
+// SendBusinessVerificationEmail emails a new link to verify the business email of a restaurant
+func (s *accountService) SendBusinessVerificationEmail(ctx context.Context, restaurantID string) error {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return invalidID("restaurant", err)
+	}
+
+	restaurant, err := s.restaurantRepo.GetByID(ctx, rid)
+	if err != nil {
+		return err
+	}
+
+	if restaurant.BusinessEmail == "" {
+		return domain.ErrNoBusinessEmail
+	}
+
+	if restaurant.IsBusinessEmailVerified() {
+		return domain.ErrBusinessEmailVerified
+	}
+
+	if err := s.throttleResend(ctx, "business_email_verification:"+restaurant.ID.String()); err != nil {
+		return err
+	}
+
+	return s.sendBusinessEmailVerification(ctx, restaurant)
+}
+
+// VerifyBusinessEmail marks the business email of the restaurant a verification link was
+// sent to as verified
+func (s *accountService) VerifyBusinessEmail(ctx context.Context, rawToken string) error {
+	restaurantID, err := s.tokenStore.Consume(ctx, businessEmailVerificationPurpose, token.Hash(rawToken))
+	if err != nil {
+		return domain.ErrInvalidVerificationLink
+	}
+
+	restaurant, err := s.restaurantRepo.GetByID(ctx, restaurantID)
+	if err != nil {
+		return domain.ErrInvalidVerificationLink
+	}
+
+	if restaurant.IsBusinessEmailVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	restaurant.BusinessEmailVerifiedAt = &now
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.restaurantRepo.Update(ctx, tx, restaurant)
+	})
+}
+
+// ChangePassword replaces the password of a user who confirmed the current one. Every
+// session but the current one is signed out.
+func (s *accountService) ChangePassword(ctx context.Context, userID string, sessionID string, req domain.ChangePasswordRequest) error {
+	user, err := reauthenticate(ctx, s.userRepo, userID, req.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := password_util.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+		return auditAccount(ctx, s.auditRepo, tx, domain.AuditActionPasswordChanged, user.ID, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = revokeOtherSessions(ctx, s.tokenCache, user.ID, sessionID)
+
+	body := fmt.Sprintf(
+		"Hello %s,\n\nThe password of your account was changed and your other sessions were signed out. If you did not do this, reset your password right away.",
+		user.Username,
+	)
+	_ = s.mailer.Send(ctx, user.Email, "Your password was changed", body)
+
+	return nil
+}
+
+// RequestEmailChange sends a confirmation link to the new address of a user who confirmed
+// their password. The address only replaces the current one once the link is opened.
+func (s *accountService) RequestEmailChange(ctx context.Context, userID string, req domain.ChangeEmailRequest) error {
+	user, err := reauthenticate(ctx, s.userRepo, userID, req.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(req.NewEmail, user.Email) {
+		return domain.ErrSameEmail
+	}
+
+	existingUser, _ := s.userRepo.GetByEmail(ctx, req.NewEmail)
+	if existingUser != nil {
+		return domain.ErrEmailTaken
+	}
+
+	if err := s.throttleResend(ctx, "email_change:"+user.ID.String()); err != nil {
+		return err
+	}
+
+	user.PendingEmail = req.NewEmail
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.userRepo.Update(ctx, tx, user)
+	})
+	if err != nil {
+		return err
+	}
+
+	secret, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokenStore.Store(ctx, emailChangePurpose, emailChangeTokenHash(secret, user.PendingEmail), user.ID, emailChangeTTL); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/confirm-email-change?token=%s.%s", s.appURL, user.ID.String(), secret)
+	body := fmt.Sprintf(
+		"Hello %s,\n\nConfirm that you want to use this address for your account by opening this link: %s\n\nThe link expires in 24 hours.",
+		user.Username, link,
+	)
+	return s.mailer.Send(ctx, req.NewEmail, "Confirm your new email address", body)
+}
+
+// emailChangeTokenHash binds the digest of an email change token to the address it confirms,
+// so the link stops working once another address is requested
+func emailChangeTokenHash(secret, email string) string {
+	return token.Hash(secret + "." + email)
+}
+
+// ConfirmEmailChange swaps in the pending email address of the user a confirmation link was
+// sent to, tells the previous address and signs the user out everywhere. The token is
+// "<user ID>.<secret>" and only matches while the address it was sent to is still pending.
+func (s *accountService) ConfirmEmailChange(ctx context.Context, rawToken string) error {
+	id, secret, ok := strings.Cut(rawToken, ".")
+	if !ok {
+		return domain.ErrInvalidConfirmationLink
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return domain.ErrInvalidConfirmationLink
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user.PendingEmail == "" {
+		return domain.ErrInvalidConfirmationLink
+	}
+
+	subjectID, err := s.tokenStore.Consume(ctx, emailChangePurpose, emailChangeTokenHash(secret, user.PendingEmail))
+	if err != nil || subjectID != user.ID {
+		return domain.ErrInvalidConfirmationLink
+	}
+
+	// The address may have been taken since the link was sent
+	existingUser, _ := s.userRepo.GetByEmail(ctx, user.PendingEmail)
+	if existingUser != nil {
+		return domain.ErrEmailTaken
+	}
+
+	previousEmail := user.Email
+	now := time.Now()
+	user.Email = user.PendingEmail
+	user.PendingEmail = ""
+	user.EmailVerifiedAt = &now
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+		return auditAccount(ctx, s.auditRepo, tx, domain.AuditActionEmailChanged, user.ID, auditChange("email", previousEmail, user.Email))
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = s.tokenCache.InvalidateUserSessions(ctx, user.ID)
+
+	body := fmt.Sprintf(
+		"Hello %s,\n\nThe email address of your account was changed to %s. If you did not do this, contact support right away.",
+		user.Username, user.Email,
+	)
+	_ = s.mailer.Send(ctx, previousEmail, "Your email address was changed", body)
+
+	return nil
+}
+
+// reauthenticate loads the user and checks they know the current password
+func reauthenticate(ctx context.Context, userRepo ports.UserRepository, userID string, currentPassword string) (*domain.User, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	user, err := userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !password_util.Verify(currentPassword, user.Password) {
+		return nil, domain.ErrIncorrectPassword
+	}
+
+	return user, nil
+}
+
+func (s *accountService) throttleResend(ctx context.Context, key string) error {
+	allowed, err := s.throttle.Allow(ctx, key, verificationResendInterval)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrVerificationEmailThrottled
+	}
+	return nil
+}
+
+func (s *accountService) sendEmailVerification(ctx context.Context, user *domain.User) error {
+	rawToken, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokenStore.Store(ctx, emailVerificationPurpose, token.Hash(rawToken), user.ID, emailVerificationTTL); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.appURL, rawToken)
+	body := fmt.Sprintf(
+		"Hello %s,\n\nPlease confirm your email address by opening this link: %s\n\nThe link expires in 48 hours.",
+		user.Username, link,
+	)
+	return s.mailer.Send(ctx, user.Email, "Verify your email address", body)
+}
+
+func (s *accountService) sendBusinessEmailVerification(ctx context.Context, restaurant *domain.Restaurant) error {
+	rawToken, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokenStore.Store(ctx, businessEmailVerificationPurpose, token.Hash(rawToken), restaurant.ID, emailVerificationTTL); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-business-email?token=%s", s.appURL, rawToken)
+	body := fmt.Sprintf(
+		"Hello,\n\nPlease confirm that %s can be reached at this address by opening this link: %s\n\nEvents can be published once the business email is verified. The link expires in 48 hours.",
+		restaurant.Name, link,
+	)
+	return s.mailer.Send(ctx, restaurant.BusinessEmail, "Verify your business email", body)
+}
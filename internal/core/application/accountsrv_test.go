@@ -0,0 +1,32 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/application"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmEmailChange(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t, application.LoginLimits{})
+
+	require.NoError(t, f.accounts.RequestEmailChange(ctx, f.user.ID.String(), domain.ChangeEmailRequest{CurrentPassword: testPassword, NewEmail: "first@example.com"}))
+	first := f.lastToken(t)
+	require.NoError(t, f.accounts.RequestEmailChange(ctx, f.user.ID.String(), domain.ChangeEmailRequest{CurrentPassword: testPassword, NewEmail: "second@example.com"}))
+	second := f.lastToken(t)
+
+	// The first link was for an address that is no longer pending
+	assert.ErrorIs(t, f.accounts.ConfirmEmailChange(ctx, first), domain.ErrInvalidConfirmationLink)
+
+	require.NoError(t, f.accounts.ConfirmEmailChange(ctx, second))
+	user, err := f.users.GetByID(ctx, f.user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "second@example.com", user.Email)
+	assert.Empty(t, user.PendingEmail)
+
+	assert.ErrorIs(t, f.accounts.ConfirmEmailChange(ctx, second), domain.ErrInvalidConfirmationLink)
+}
@@ -0,0 +1,437 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	password_util "github.com/SOU9OUR-DCF/dcf-backend.git/pkg/password"
+	"github.com/google/uuid"
+)
+
+type adminService struct {
+	txManager      ports.TransactionManager
+	userRepo       ports.UserRepository
+	restaurantRepo ports.RestaurantRepository
+	volunteerRepo  ports.VolunteerRepository
+	eventRepo      ports.EventRepository
+	branchRepo     ports.BranchRepository
+	appRepo        ports.VolunteerApplicationRepository
+	eventVolRepo   ports.EventVolunteerRepository
+	policyRepo     ports.SecurityPolicyRepository
+	auditRepo      ports.AuditLogRepository
+	tokenCache     ports.TokenCache
+}
+
+func NewAdminService(
+	txManager ports.TransactionManager,
+	userRepo ports.UserRepository,
+	restaurantRepo ports.RestaurantRepository,
+	volunteerRepo ports.VolunteerRepository,
+	eventRepo ports.EventRepository,
+	branchRepo ports.BranchRepository,
+	appRepo ports.VolunteerApplicationRepository,
+	eventVolRepo ports.EventVolunteerRepository,
+	policyRepo ports.SecurityPolicyRepository,
+	auditRepo ports.AuditLogRepository,
+	tokenCache ports.TokenCache,
+) ports.AdminService {
+	return &adminService{
+		txManager:      txManager,
+		userRepo:       userRepo,
+		restaurantRepo: restaurantRepo,
+		volunteerRepo:  volunteerRepo,
+		eventRepo:      eventRepo,
+		branchRepo:     branchRepo,
+		appRepo:        appRepo,
+		eventVolRepo:   eventVolRepo,
+		policyRepo:     policyRepo,
+		auditRepo:      auditRepo,
+		tokenCache:     tokenCache,
+	}
+}
+
+func (s *adminService) CreateAdmin(ctx context.Context, email, username, password string) (*domain.User, error) {
+	if len(password) < 12 {
+		return nil, domain.ErrAdminPasswordTooShort
+	}
+
+	existingUser, _ := s.userRepo.GetByEmail(ctx, email)
+	if existingUser != nil {
+		return nil, domain.ErrEmailTaken
+	}
+
+	existingUsername, _ := s.userRepo.GetByUsername(ctx, username)
+	if existingUsername != nil {
+		return nil, domain.ErrUsernameTaken
+	}
+
+	hashedPassword, err := password_util.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admins are created from the command line by someone who controls the address
+	now := time.Now()
+	user := &domain.User{
+		Email:           email,
+		Username:        username,
+		Password:        hashedPassword,
+		Type:            domain.UserTypeAdmin,
+		EmailVerifiedAt: &now,
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.userRepo.Create(ctx, tx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *adminService) ListUsers(ctx context.Context, filter domain.ListFilter) ([]*domain.User, int, error) {
+	filter.Normalize()
+	return s.userRepo.List(ctx, filter)
+}
+
+func (s *adminService) GetUser(ctx context.Context, id string) (*domain.User, interface{}, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nil, invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := profileOf(ctx, s.restaurantRepo, s.volunteerRepo, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, profile, nil
+}
+
+func (s *adminService) UpdateUser(ctx context.Context, id string, update domain.AdminUserUpdate) (*domain.User, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	before := *user
+
+	if update.Email != nil && *update.Email != user.Email {
+		existingUser, _ := s.userRepo.GetByEmail(ctx, *update.Email)
+		if existingUser != nil {
+			return nil, domain.ErrEmailTaken
+		}
+		user.Email = *update.Email
+	}
+
+	if update.Username != nil && *update.Username != user.Username {
+		existingUsername, _ := s.userRepo.GetByUsername(ctx, *update.Username)
+		if existingUsername != nil {
+			return nil, domain.ErrUsernameTaken
+		}
+		user.Username = *update.Username
+	}
+
+	roleChanged := false
+	if update.UserType != nil && *update.UserType != user.Type {
+		// Restaurant and volunteer accounts own a profile that the new role would not have
+		if hasProfile(user.Type) || hasProfile(*update.UserType) {
+			return nil, domain.ErrInvalidTypeChange
+		}
+		user.Type = *update.UserType
+		roleChanged = true
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionUserUpdated, domain.AuditTargetUser, user.ID.String())
+		entry.Changes = auditDiff(before, user)
+		return s.auditRepo.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Existing sessions carry the old role
+	if roleChanged {
+		_ = s.tokenCache.InvalidateUserSessions(ctx, user.ID)
+	}
+
+	return user, nil
+}
+
+func (s *adminService) SuspendUser(ctx context.Context, id string, reason string) error {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	if user.Type == domain.UserTypeAdmin {
+		return domain.ErrAdminNotSuspendable
+	}
+
+	now := time.Now()
+	user.SuspendedAt = &now
+	user.SuspensionReason = reason
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionUserSuspended, domain.AuditTargetUser, user.ID.String())
+		entry.Detail = reason
+		return s.auditRepo.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Log the user out everywhere, there may be no active session
+	_ = s.tokenCache.InvalidateUserSessions(ctx, user.ID)
+
+	return nil
+}
+
+func (s *adminService) ReactivateUser(ctx context.Context, id string) error {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	user.SuspendedAt = nil
+	user.SuspensionReason = ""
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Create(ctx, tx, newAuditEntry(ctx, domain.AuditActionUserReactivated, domain.AuditTargetUser, user.ID.String()))
+	})
+}
+
+func (s *adminService) ListRestaurants(ctx context.Context, filter domain.ListFilter) ([]*domain.Restaurant, int, error) {
+	filter.Normalize()
+	return s.restaurantRepo.List(ctx, filter)
+}
+
+func (s *adminService) GetRestaurant(ctx context.Context, id string) (*domain.Restaurant, error) {
+	rid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	return s.restaurantRepo.GetByID(ctx, rid)
+}
+
+func (s *adminService) UpdateRestaurant(ctx context.Context, id string, update domain.AdminRestaurantUpdate) (*domain.Restaurant, error) {
+	rid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	restaurant, err := s.restaurantRepo.GetByID(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+	before := *restaurant
+
+	if update.Name != nil {
+		restaurant.Name = *update.Name
+	}
+	if update.Address != nil {
+		restaurant.Address = *update.Address
+	}
+	if update.ContactNumber != nil {
+		restaurant.ContactNumber = *update.ContactNumber
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.restaurantRepo.Update(ctx, tx, restaurant); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionRestaurantUpdated, domain.AuditTargetRestaurant, restaurant.ID.String())
+		entry.Changes = auditDiff(before, restaurant)
+		return s.auditRepo.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restaurant, nil
+}
+
+func (s *adminService) ListEvents(ctx context.Context, filter domain.ListFilter) ([]*domain.Event, int, error) {
+	filter.Normalize()
+	return s.eventRepo.List(ctx, filter)
+}
+
+func (s *adminService) UpdateEvent(ctx context.Context, id string, update domain.AdminEventUpdate) (*domain.Event, error) {
+	eid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("event", err)
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, eid)
+	if err != nil {
+		return nil, err
+	}
+	before := *event
+
+	// The event can only move to another branch of the restaurant that created it
+	if update.BranchID != nil {
+		branch, err := s.branchRepo.GetByID(ctx, *update.BranchID)
+		if err != nil || branch.RestaurantID != event.RestaurantID {
+			return nil, domain.ErrBranchNotInRestaurant
+		}
+		event.BranchID = update.BranchID
+	}
+	if update.Title != nil {
+		event.Title = *update.Title
+	}
+	if update.Description != nil {
+		event.Description = *update.Description
+	}
+	if update.Date != nil {
+		event.Date = *update.Date
+	}
+	if update.StartTime != nil {
+		event.StartTime = *update.StartTime
+	}
+	if update.EndTime != nil {
+		event.EndTime = *update.EndTime
+	}
+	if update.Location != nil {
+		event.Location = *update.Location
+	}
+	if update.MaxGuests != nil {
+		event.MaxGuests = *update.MaxGuests
+	}
+	if update.MaxVolunteers != nil {
+		event.MaxVolunteers = *update.MaxVolunteers
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.eventRepo.Update(ctx, tx, event); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionEventUpdated, domain.AuditTargetEvent, event.ID.String())
+		entry.Changes = auditDiff(before, event)
+		return s.auditRepo.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (s *adminService) ListApplications(ctx context.Context, filter domain.ListFilter) ([]*domain.VolunteerApplication, int, error) {
+	filter.Normalize()
+	return s.appRepo.List(ctx, filter)
+}
+
+func (s *adminService) UpdateApplicationStatus(ctx context.Context, id string, status string) error {
+	appID, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("application", err)
+	}
+
+	app, err := s.appRepo.GetByID(ctx, appID)
+	if err != nil {
+		return err
+	}
+
+	if app.Status == status {
+		return nil
+	}
+
+	if app.Status == "approved" {
+		return domain.ErrApplicationApproved
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.appRepo.UpdateStatus(ctx, tx, appID, status); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionApplicationStatusChanged, domain.AuditTargetApplication, app.ID.String())
+		entry.Changes = auditChange("status", app.Status, status)
+		if err := s.auditRepo.Create(ctx, tx, entry); err != nil {
+			return err
+		}
+
+		if status != "approved" {
+			return nil
+		}
+
+		// Approving assigns the volunteer to the event, same as a restaurant approval
+		return s.eventVolRepo.Create(ctx, tx, &domain.EventVolunteer{
+			EventID:     app.EventID,
+			VolunteerID: app.VolunteerID,
+			Role:        app.Role,
+			CheckedIn:   false,
+		})
+	})
+}
+
+func (s *adminService) GetSecurityPolicy(ctx context.Context) (*domain.SecurityPolicy, error) {
+	return s.policyRepo.Get(ctx)
+}
+
+func (s *adminService) UpdateSecurityPolicy(ctx context.Context, req domain.UpdateSecurityPolicyRequest) (*domain.SecurityPolicy, error) {
+	policy, err := s.policyRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *policy
+	policy.RequireOwnerTwoFactor = *req.RequireOwnerTwoFactor
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.policyRepo.Save(ctx, tx, policy); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionSecurityPolicyUpdated, domain.AuditTargetSecurityPolicy, "")
+		entry.Changes = auditDiff(before, policy)
+		return s.auditRepo.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func hasProfile(userType domain.UserType) bool {
+	return userType == domain.UserTypeRestaurant || userType == domain.UserTypeVolunteer
+}
@@ -0,0 +1,162 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/token"
+	"github.com/google/uuid"
+)
+
+const (
+	// apiKeyPrefix marks API keys, so they are recognized in logs and by secret scanners
+	apiKeyPrefix = "dcf_"
+	// Last used times are written at most this often, not on every request
+	apiKeyTouchInterval = time.Minute
+)
+
+type apiKeyService struct {
+	txManager  ports.TransactionManager
+	apiKeyRepo ports.APIKeyRepository
+	userRepo   ports.UserRepository
+	memberRepo ports.RestaurantMemberRepository
+	auditRepo  ports.AuditLogRepository
+}
+
+func NewAPIKeyService(
+	txManager ports.TransactionManager,
+	apiKeyRepo ports.APIKeyRepository,
+	userRepo ports.UserRepository,
+	memberRepo ports.RestaurantMemberRepository,
+	auditRepo ports.AuditLogRepository,
+) ports.APIKeyService {
+	return &apiKeyService{
+		txManager:  txManager,
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		memberRepo: memberRepo,
+		auditRepo:  auditRepo,
+	}
+}
+
+// CreateAPIKey creates a key for the restaurant acting with the permissions of the member
+// creating it. The key is only returned here.
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, restaurantID string, userID string, req domain.CreateAPIKeyRequest) (*domain.CreatedAPIKey, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	scopes := domain.APIKeyScopes{}
+	for _, scope := range req.Scopes {
+		if !scope.IsValid() {
+			return nil, domain.NewValidationError("unknown_scope", fmt.Sprintf("unknown scope %q", scope))
+		}
+		scopes = append(scopes, scope)
+	}
+
+	secret, err := token.Generate()
+	if err != nil {
+		return nil, err
+	}
+	rawKey := apiKeyPrefix + secret
+
+	key := &domain.APIKey{
+		RestaurantID: rid,
+		Name:         req.Name,
+		Prefix:       rawKey[:len(apiKeyPrefix)+8],
+		KeyHash:      token.Hash(rawKey),
+		Scopes:       scopes,
+		CreatedBy:    uid,
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.apiKeyRepo.Create(ctx, tx, key); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionAPIKeyCreated, domain.AuditTargetAPIKey, key.ID.String())
+		entry.Detail = key.Name
+		return s.auditRepo.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedAPIKey{APIKey: *key, Key: rawKey}, nil
+}
+
+func (s *apiKeyService) ListAPIKeys(ctx context.Context, restaurantID string) ([]*domain.APIKey, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	return s.apiKeyRepo.GetByRestaurantID(ctx, rid)
+}
+
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, restaurantID string, id string) error {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return invalidID("restaurant", err)
+	}
+
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("API key", err)
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil || key.RestaurantID != rid {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	if key.IsRevoked() {
+		return nil
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.apiKeyRepo.Revoke(ctx, tx, keyID); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Create(ctx, tx, newAuditEntry(ctx, domain.AuditActionAPIKeyRevoked, domain.AuditTargetAPIKey, keyID.String()))
+	})
+}
+
+// AuthenticateAPIKey returns the key together with the member it acts for. A key stops
+// working when it is revoked, or when its creator is suspended or leaves the restaurant.
+func (s *apiKeyService) AuthenticateAPIKey(ctx context.Context, rawKey string) (*domain.APIKey, *domain.User, *domain.RestaurantMember, error) {
+	invalid := domain.ErrInvalidAPIKey
+
+	key, err := s.apiKeyRepo.GetByHash(ctx, token.Hash(rawKey))
+	if err != nil || key.IsRevoked() {
+		return nil, nil, nil, invalid
+	}
+
+	user, err := s.userRepo.GetByID(ctx, key.CreatedBy)
+	if err != nil || user.IsSuspended() {
+		return nil, nil, nil, invalid
+	}
+
+	member, err := s.memberRepo.GetByRestaurantAndUser(ctx, key.RestaurantID, key.CreatedBy)
+	if err != nil || member.Restaurant == nil {
+		return nil, nil, nil, invalid
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		_ = s.apiKeyRepo.TouchLastUsed(ctx, key.ID, now)
+		key.LastUsedAt = &now
+	}
+
+	return key, user, member, nil
+}
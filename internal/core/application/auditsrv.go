@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+)
+
+type auditService struct {
+	auditRepo ports.AuditLogRepository
+	retention time.Duration
+}
+
+func NewAuditService(auditRepo ports.AuditLogRepository, retention time.Duration) ports.AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+		retention: retention,
+	}
+}
+
+// Record stores an entry written outside the services, such as the requests made through
+// an impersonated session
+func (s *auditService) Record(ctx context.Context, entry *domain.AuditLog) error {
+	return s.auditRepo.Create(ctx, nil, entry)
+}
+
+func (s *auditService) ListAuditLogs(ctx context.Context, filter domain.AuditLogFilter) ([]*domain.AuditLog, int, error) {
+	filter.Normalize()
+	return s.auditRepo.List(ctx, filter)
+}
+
+// PurgeExpired removes the entries older than the retention period, zero keeps them forever
+func (s *auditService) PurgeExpired(ctx context.Context) (int, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	return s.auditRepo.DeleteBefore(ctx, time.Now().Add(-s.retention))
+}
+
+// newAuditEntry attributes an action to the actor and request of ctx. The services store
+// it in the transaction of the change, so there is no change without its entry.
+func newAuditEntry(ctx context.Context, action domain.AuditAction, targetType string, targetID string) *domain.AuditLog {
+	auditCtx := domain.AuditContextFrom(ctx)
+	return &domain.AuditLog{
+		ActorID:        auditCtx.ActorID,
+		ImpersonatorID: auditCtx.ImpersonatorID,
+		APIKeyID:       auditCtx.APIKeyID,
+		Action:         action,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Method:         auditCtx.Method,
+		Path:           auditCtx.Path,
+		IPAddress:      auditCtx.IPAddress,
+		UserAgent:      auditCtx.UserAgent,
+	}
+}
+
+// auditChange records a single field going from one value to another
+func auditChange(field string, from, to interface{}) domain.AuditChanges {
+	return domain.AuditChanges{field: {From: from, To: to}}
+}
+
+// auditDiff compares the JSON forms of a record before and after a change, fields hidden
+// from JSON such as password hashes never end up in the log
+func auditDiff(before, after interface{}) domain.AuditChanges {
+	from, to := jsonFields(before), jsonFields(after)
+
+	changes := domain.AuditChanges{}
+	for field, value := range to {
+		if !reflect.DeepEqual(from[field], value) {
+			changes[field] = domain.AuditChange{From: from[field], To: value}
+		}
+	}
+	for field, value := range from {
+		if _, ok := to[field]; !ok {
+			changes[field] = domain.AuditChange{From: value}
+		}
+	}
+
+	// Timestamps maintained by the database are no news
+	delete(changes, "updated_at")
+
+	return changes
+}
+
+func jsonFields(record interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
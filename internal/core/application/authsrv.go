@@ -2,7 +2,9 @@ package application
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,44 +13,78 @@ import (
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/jwt"
 	password_util "github.com/SOU9OUR-DCF/dcf-backend.git/pkg/password"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/token"
 )
 
+// Last seen times are written at most this often, not on every request
+const sessionTouchInterval = time.Minute
+
 type authService struct {
 	txManager      ports.TransactionManager
 	userRepo       ports.UserRepository
 	restaurantRepo ports.RestaurantRepository
+	branchRepo     ports.BranchRepository
 	volunteerRepo  ports.VolunteerRepository
+	memberRepo     ports.RestaurantMemberRepository
+	invitationRepo ports.RestaurantInvitationRepository
+	identityRepo   ports.ExternalIdentityRepository
+	auditRepo      ports.AuditLogRepository
+	providers      map[string]ports.IdentityProvider
 	tokenCache     ports.TokenCache
+	lockout        ports.LockoutService
+	twoFactor      ports.TwoFactorService
+	accounts       ports.AccountService
 	jwtService     *jwt.Service
+	refreshTTL     time.Duration
 }
 
 func NewAuthService(
 	txManager ports.TransactionManager,
 	userRepo ports.UserRepository,
 	restaurantRepo ports.RestaurantRepository,
+	branchRepo ports.BranchRepository,
 	volunteerRepo ports.VolunteerRepository,
+	memberRepo ports.RestaurantMemberRepository,
+	invitationRepo ports.RestaurantInvitationRepository,
+	identityRepo ports.ExternalIdentityRepository,
+	auditRepo ports.AuditLogRepository,
+	providers map[string]ports.IdentityProvider,
 	tokenCache ports.TokenCache,
+	lockout ports.LockoutService,
+	twoFactor ports.TwoFactorService,
+	accounts ports.AccountService,
 	jwtService *jwt.Service,
+	refreshTTL time.Duration,
 ) ports.AuthService {
 	return &authService{
 		txManager:      txManager,
 		userRepo:       userRepo,
 		restaurantRepo: restaurantRepo,
+		branchRepo:     branchRepo,
 		volunteerRepo:  volunteerRepo,
+		memberRepo:     memberRepo,
+		invitationRepo: invitationRepo,
+		identityRepo:   identityRepo,
+		auditRepo:      auditRepo,
+		providers:      providers,
 		tokenCache:     tokenCache,
+		lockout:        lockout,
+		twoFactor:      twoFactor,
+		accounts:       accounts,
 		jwtService:     jwtService,
+		refreshTTL:     refreshTTL,
 	}
 }
 
-func (s *authService) registerUser(ctx context.Context, tx interface{}, email, username, password string, userType domain.UserType) (*domain.User, error) {
+func (s *authService) registerUser(ctx context.Context, tx interface{}, email, username, password string, userType domain.UserType, emailVerified bool) (*domain.User, error) {
 	existingUser, _ := s.userRepo.GetByEmail(ctx, email)
 	if existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, domain.ErrEmailTaken
 	}
 
 	existingUsername, _ := s.userRepo.GetByUsername(ctx, username)
 	if existingUsername != nil {
-		return nil, errors.New("username already taken")
+		return nil, domain.ErrUsernameTaken
 	}
 
 	hashedPassword, err := password_util.Hash(password)
@@ -66,6 +102,9 @@ func (s *authService) registerUser(ctx context.Context, tx interface{}, email, u
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	if emailVerified {
+		user.EmailVerifiedAt = &now
+	}
 
 	if err := s.userRepo.Create(ctx, tx, user); err != nil {
 		return nil, err
@@ -74,61 +113,218 @@ func (s *authService) registerUser(ctx context.Context, tx interface{}, email, u
 	return user, nil
 }
 
-func (s *authService) createAuthResponse(ctx context.Context, user *domain.User, profile interface{}) (*domain.AuthResponse, domain.Token, error) {
-	token, exp, err := s.jwtService.GenerateToken(user.ID.String())
-	if err != nil {
-		return nil, domain.Token(""), err
+// createAuthResponse opens a new session for the device and issues its first tokens
+func (s *authService) createAuthResponse(ctx context.Context, user *domain.User, profile interface{}, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error) {
+	now := time.Now()
+	session := &domain.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  device.UserAgent,
+		IPAddress:  device.IPAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
 	}
 
-	if err := s.tokenCache.StoreToken(ctx, user.ID, token, time.Until(exp)); err != nil {
-		return nil, domain.Token(""), err
+	tokens, exp, err := s.issueTokens(ctx, user, session)
+	if err != nil {
+		return nil, domain.TokenPair{}, err
 	}
 
 	return &domain.AuthResponse{
-		ExpiresAt: exp,
-		User:      *user,
-		Profile:   profile,
-	}, domain.NewToken(token), nil
+		ExpiresAt:        exp,
+		RefreshExpiresAt: session.ExpiresAt,
+		User:             *user,
+		Profile:          profile,
+	}, tokens, nil
+}
+
+// issueTokens signs an access token for the session and rotates its refresh token. The
+// refresh token is "<session ID>.<secret>", only the digest of the secret is kept.
+func (s *authService) issueTokens(ctx context.Context, user *domain.User, session *domain.Session) (domain.TokenPair, time.Time, error) {
+	accessToken, exp, err := s.jwtService.GenerateToken(user.ID.String(), session.ID.String(), string(user.Type))
+	if err != nil {
+		return domain.TokenPair{}, time.Time{}, err
+	}
+
+	secret, err := token.Generate()
+	if err != nil {
+		return domain.TokenPair{}, time.Time{}, err
+	}
+	previousHash := session.RefreshTokenHash
+	session.RefreshTokenHash = token.Hash(secret)
+	session.ExpiresAt = time.Now().Add(s.refreshTTL)
+
+	if previousHash == "" {
+		err = s.tokenCache.StoreSession(ctx, session, s.refreshTTL)
+	} else {
+		// Two requests with the same refresh token both get past sessionForRefreshToken, only
+		// the first to swap the digest wins. The other presented a token that was rotated by
+		// then, so the session is revoked like for any other reuse.
+		err = s.tokenCache.RotateRefreshToken(ctx, session, previousHash, s.refreshTTL)
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			_ = s.tokenCache.InvalidateSession(ctx, session.UserID, session.ID)
+		}
+	}
+	if err != nil {
+		return domain.TokenPair{}, time.Time{}, err
+	}
+
+	return domain.TokenPair{
+		AccessToken:  domain.NewToken(accessToken),
+		RefreshToken: domain.NewToken(session.ID.String() + "." + secret),
+	}, exp, nil
+}
+
+// sessionForRefreshToken returns the session a refresh token belongs to. Presenting a
+// refresh token that was already rotated means it leaked, so the session is revoked. Any
+// other secret is just rejected, the session ID alone is no proof of anything since it is
+// the ID of every access token of the session.
+func (s *authService) sessionForRefreshToken(ctx context.Context, refreshToken string) (*domain.Session, error) {
+	id, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	session, err := s.tokenCache.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	hash := token.Hash(secret)
+	if subtle.ConstantTimeCompare([]byte(session.RefreshTokenHash), []byte(hash)) == 1 {
+		return session, nil
+	}
+
+	for _, rotated := range session.RotatedTokenHashes {
+		if subtle.ConstantTimeCompare([]byte(rotated), []byte(hash)) == 1 {
+			_ = s.tokenCache.InvalidateSession(ctx, session.UserID, session.ID)
+			return nil, domain.ErrRefreshTokenReused
+		}
+	}
+
+	return nil, domain.ErrInvalidRefreshToken
+}
+
+func (s *authService) RegisterRestaurant(ctx context.Context, req domain.RestaurantRegisterRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error) {
+	return s.RegisterOrganization(ctx, domain.OrganizationRegisterRequest{
+		Username:         req.Username,
+		Email:            req.Email,
+		Password:         req.Password,
+		OrganizationType: domain.OrganizationTypeRestaurant,
+		Name:             req.Name,
+		BusinessEmail:    req.BusinessEmail,
+		ContactNumber:    req.ContactNumber,
+		Address:          req.Address,
+	}, device)
 }
 
-func (s *authService) RegisterRestaurant(ctx context.Context, req domain.RestaurantRegisterRequest) (*domain.AuthResponse, domain.Token, error) {
+func (s *authService) RegisterOrganization(ctx context.Context, req domain.OrganizationRegisterRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error) {
+	if !req.OrganizationType.IsValid() {
+		return nil, domain.TokenPair{}, domain.ErrInvalidOrganizationType
+	}
+
 	var user *domain.User
 	var profile *domain.Restaurant
 
 	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
 		var err error
 		// Register user with transaction
-		user, err = s.registerUser(ctx, tx, req.Email, req.Username, req.Password, domain.UserTypeRestaurant)
+		user, err = s.registerUser(ctx, tx, req.Email, req.Username, req.Password, domain.UserTypeRestaurant, false)
 		if err != nil {
 			return err
 		}
 
-		// Create restaurant profile with the same transaction
+		// Create the organization profile with the same transaction
 		profile = &domain.Restaurant{
-			UserID:        user.ID,
+			UserID:           user.ID,
+			Name:             req.Name,
+			Address:          req.Address,
+			ContactNumber:    req.ContactNumber,
+			BusinessEmail:    req.BusinessEmail,
+			OrganizationType: req.OrganizationType,
+		}
+
+		if err := s.restaurantRepo.Create(ctx, tx, profile); err != nil {
+			return err
+		}
+
+		// Every organization starts out with a single branch at its address
+		if err := s.branchRepo.Create(ctx, tx, &domain.Branch{
+			RestaurantID:  profile.ID,
 			Name:          req.Name,
 			Address:       req.Address,
 			ContactNumber: req.ContactNumber,
+		}); err != nil {
+			return err
+		}
+
+		// The registering user owns the organization
+		return s.memberRepo.Create(ctx, tx, &domain.RestaurantMember{
+			RestaurantID: profile.ID,
+			UserID:       user.ID,
+			Role:         domain.MembershipRoleOwner,
+		})
+	})
+
+	if err != nil {
+		return nil, domain.TokenPair{}, err
+	}
+
+	// The account is usable without the emails, they can be sent again later
+	_ = s.accounts.SendVerificationEmail(ctx, user.ID.String())
+	_ = s.accounts.SendBusinessVerificationEmail(ctx, profile.ID.String())
+
+	return s.createAuthResponse(ctx, user, profile, device)
+}
+
+func (s *authService) RegisterStaff(ctx context.Context, req domain.StaffRegisterRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error) {
+	invitation, err := findPendingInvitation(ctx, s.invitationRepo, req.Token)
+	if err != nil {
+		return nil, domain.TokenPair{}, err
+	}
+
+	var user *domain.User
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		var err error
+		// The invitation proves the email address belongs to the invitee
+		user, err = s.registerUser(ctx, tx, invitation.Email, req.Username, req.Password, domain.UserTypeRestaurant, true)
+		if err != nil {
+			return err
+		}
+
+		if err := s.memberRepo.Create(ctx, tx, &domain.RestaurantMember{
+			RestaurantID: invitation.RestaurantID,
+			UserID:       user.ID,
+			Role:         invitation.Role,
+			BranchID:     invitation.BranchID,
+		}); err != nil {
+			return err
 		}
 
-		return s.restaurantRepo.Create(ctx, tx, profile)
+		return s.invitationRepo.MarkAccepted(ctx, tx, invitation.ID)
 	})
 
 	if err != nil {
-		return nil, domain.Token(""), err
+		return nil, domain.TokenPair{}, err
 	}
 
-	return s.createAuthResponse(ctx, user, profile)
+	return s.createAuthResponse(ctx, user, invitation.Restaurant, device)
 }
 
-func (s *authService) RegisterVolunteer(ctx context.Context, req domain.VolunteerRegisterRequest) (*domain.AuthResponse, domain.Token, error) {
+func (s *authService) RegisterVolunteer(ctx context.Context, req domain.VolunteerRegisterRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error) {
 	var user *domain.User
 	var profile *domain.Volunteer
 
 	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
 		var err error
 		// Register user with transaction
-		user, err = s.registerUser(ctx, tx, req.Email, req.Username, req.Password, domain.UserTypeVolunteer)
+		user, err = s.registerUser(ctx, tx, req.Email, req.Username, req.Password, domain.UserTypeVolunteer, false)
 		if err != nil {
 			return err
 		}
@@ -144,108 +340,435 @@ func (s *authService) RegisterVolunteer(ctx context.Context, req domain.Voluntee
 	})
 
 	if err != nil {
-		return nil, domain.Token(""), err
+		return nil, domain.TokenPair{}, err
 	}
 
-	return s.createAuthResponse(ctx, user, profile)
+	_ = s.accounts.SendVerificationEmail(ctx, user.ID.String())
+
+	return s.createAuthResponse(ctx, user, profile, device)
 }
 
-func (s *authService) Login(ctx context.Context, req domain.LoginRequest) (*domain.AuthResponse, domain.Token, error) {
+// Login checks the credentials and signs the user in. Users with two-factor authentication
+// get a challenge instead of tokens, to be completed with CompleteTwoFactorLogin.
+func (s *authService) Login(ctx context.Context, req domain.LoginRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, *domain.TwoFactorChallenge, error) {
+	if err := s.lockout.CheckLogin(ctx, req.Email, device); err != nil {
+		return nil, domain.TokenPair{}, nil, err
+	}
+
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, domain.Token(""), errors.New("invalid credentials")
+		s.lockout.RecordFailure(ctx, nil, req.Email, device, "unknown_email")
+		return nil, domain.TokenPair{}, nil, domain.ErrInvalidCredentials
+	}
+
+	// Locked accounts are refused before the password is checked, so guessing stops paying off
+	if user.IsLocked() {
+		return nil, domain.TokenPair{}, nil, domain.ErrAccountLocked
 	}
 
 	if !password_util.Verify(req.Password, user.Password) {
-		return nil, domain.Token(""), errors.New("invalid credentials")
+		s.lockout.RecordFailure(ctx, user, req.Email, device, "wrong_password")
+		return nil, domain.TokenPair{}, nil, domain.ErrInvalidCredentials
+	}
+
+	if user.IsSuspended() {
+		return nil, domain.TokenPair{}, nil, domain.ErrAccountSuspended
+	}
+
+	// The failure count is kept until the second factor passed too, so guessing codes with
+	// a known password still leads to a lockout
+	if user.IsTwoFactorEnabled() {
+		challenge, err := s.twoFactor.CreateChallenge(ctx, user)
+		return nil, domain.TokenPair{}, challenge, err
+	}
+
+	_ = s.lockout.ResetFailures(ctx, req.Email)
+
+	res, tokens, err := s.signIn(ctx, user, device)
+	return res, tokens, nil, err
+}
+
+// StartOIDCLogin prepares a sign-in at the named OpenID Connect provider
+func (s *authService) StartOIDCLogin(ctx context.Context, providerName string) (*domain.OIDCAuthorization, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, domain.ErrUnknownIdentityProvider
+	}
+
+	auth := &domain.OIDCAuthorization{}
+	for _, secret := range []*string{&auth.State, &auth.Nonce, &auth.CodeVerifier} {
+		value, err := token.Generate()
+		if err != nil {
+			return nil, err
+		}
+		*secret = value
+	}
+
+	url, err := provider.AuthCodeURL(ctx, auth.State, auth.Nonce, auth.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	auth.URL = url
+
+	return auth, nil
+}
+
+// CompleteOIDCLogin signs in the user the provider vouches for. An unknown identity is linked
+// to the account with the same email address when both sides verified it, otherwise a new
+// volunteer account is created. Two-factor authentication still applies.
+func (s *authService) CompleteOIDCLogin(ctx context.Context, providerName string, callback domain.OIDCCallback, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, *domain.TwoFactorChallenge, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, domain.TokenPair{}, nil, domain.ErrUnknownIdentityProvider
+	}
+
+	claims, err := provider.Exchange(ctx, callback.Code, callback.CodeVerifier, callback.Nonce)
+	if err != nil {
+		return nil, domain.TokenPair{}, nil, domain.ErrIdentityProviderRejected
+	}
+
+	user, err := s.userForExternalIdentity(ctx, providerName, claims)
+	if err != nil {
+		return nil, domain.TokenPair{}, nil, err
+	}
+
+	return s.CompleteSignIn(ctx, user, device)
+}
+
+func (s *authService) userForExternalIdentity(ctx context.Context, providerName string, claims *domain.ExternalClaims) (*domain.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, providerName, claims.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(ctx, identity.UserID)
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, domain.ErrProviderEmailUnverified
+	}
+
+	identity = &domain.ExternalIdentity{
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+
+	if user, _ := s.userRepo.GetByEmail(ctx, claims.Email); user != nil {
+		// Whoever registered an address without verifying it may not own it, linking would
+		// hand them the provider's account
+		if !user.IsEmailVerified() {
+			return nil, domain.ErrIdentityLinkRequired
+		}
+
+		identity.UserID = user.ID
+		err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+			return s.identityRepo.Create(ctx, tx, identity)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	return s.registerExternalVolunteer(ctx, identity, claims)
+}
+
+// registerExternalVolunteer creates a volunteer account for someone signing in through a
+// provider for the first time. Its password is random, a password reset sets a usable one.
+func (s *authService) registerExternalVolunteer(ctx context.Context, identity *domain.ExternalIdentity, claims *domain.ExternalClaims) (*domain.User, error) {
+	password, err := token.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := s.availableUsername(ctx, claims.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	fullName := claims.Name
+	if fullName == "" {
+		fullName = username
+	}
+
+	var user *domain.User
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		var err error
+		user, err = s.registerUser(ctx, tx, claims.Email, username, password, domain.UserTypeVolunteer, true)
+		if err != nil {
+			return err
+		}
+
+		if err := s.volunteerRepo.Create(ctx, tx, &domain.Volunteer{
+			UserID:   user.ID,
+			FullName: fullName,
+		}); err != nil {
+			return err
+		}
+
+		identity.UserID = user.ID
+		return s.identityRepo.Create(ctx, tx, identity)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// availableUsername derives a free username from the local part of the email address
+func (s *authService) availableUsername(ctx context.Context, email string) (string, error) {
+	local, _, _ := strings.Cut(email, "@")
+	base := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return -1
+	}, local)
+	if base == "" {
+		base = "volunteer"
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		if existing, _ := s.userRepo.GetByUsername(ctx, candidate); existing == nil {
+			return candidate, nil
+		}
+
+		suffix, err := token.Generate()
+		if err != nil {
+			return "", err
+		}
+		candidate = base + "_" + strings.ToLower(normalizeRecoveryCode(suffix)[:4])
+	}
+
+	return "", domain.ErrUsernameUnavailable
+}
+
+// CompleteTwoFactorLogin signs in a user who passed the password step with a TOTP code or
+// a recovery code. Wrong codes count as failed sign-ins.
+func (s *authService) CompleteTwoFactorLogin(ctx context.Context, req domain.TwoFactorLoginRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error) {
+	user, err := s.twoFactor.VerifyChallenge(ctx, req, device)
+	if err != nil {
+		return nil, domain.TokenPair{}, err
+	}
+
+	if user.IsSuspended() {
+		return nil, domain.TokenPair{}, domain.ErrAccountSuspended
+	}
+
+	return s.signIn(ctx, user, device)
+}
+
+// CompleteSignIn signs in a user another way of signing in identified, such as a provider
+// or an emailed link. Users with two-factor authentication get a challenge instead.
+func (s *authService) CompleteSignIn(ctx context.Context, user *domain.User, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, *domain.TwoFactorChallenge, error) {
+	if user.IsLocked() {
+		return nil, domain.TokenPair{}, nil, domain.ErrAccountLocked
+	}
+
+	if user.IsSuspended() {
+		return nil, domain.TokenPair{}, nil, domain.ErrAccountSuspended
+	}
+
+	if user.IsTwoFactorEnabled() {
+		challenge, err := s.twoFactor.CreateChallenge(ctx, user)
+		return nil, domain.TokenPair{}, challenge, err
 	}
 
-	var profile interface{}
-	switch user.Type {
-	case domain.UserTypeRestaurant:
-		profile, err = s.restaurantRepo.GetByUserID(ctx, user.ID)
-	case domain.UserTypeVolunteer:
-		profile, err = s.volunteerRepo.GetByUserID(ctx, user.ID)
+	res, tokens, err := s.signIn(ctx, user, device)
+	return res, tokens, nil, err
+}
+
+// signIn opens a session for a user whose credentials were checked
+func (s *authService) signIn(ctx context.Context, user *domain.User, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error) {
+	profile, err := profileOf(ctx, s.restaurantRepo, s.volunteerRepo, user)
+	if err != nil {
+		return nil, domain.TokenPair{}, err
 	}
 
+	res, tokens, err := s.createAuthResponse(ctx, user, profile, device)
 	if err != nil {
-		return nil, domain.Token(""), err
+		return nil, domain.TokenPair{}, err
 	}
 
-	return s.createAuthResponse(ctx, user, profile)
+	_ = auditAccount(ctx, s.auditRepo, nil, domain.AuditActionLoginSucceeded, user.ID, nil)
+
+	// Owners the policy requires 2FA from are signed in, but only to enroll
+	res.TwoFactorSetupRequired, _ = s.twoFactor.TwoFactorEnrollmentRequired(ctx, user.ID.String())
+
+	return res, tokens, nil
 }
 
-func (s *authService) ValidateToken(ctx context.Context, token string) (*domain.User, interface{}, error) {
+func (s *authService) ValidateToken(ctx context.Context, token string) (*domain.User, interface{}, *domain.Session, error) {
 	claims, err := s.jwtService.ValidateToken(token)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, domain.ErrInvalidToken
 	}
 
 	userID, err := uuid.Parse(claims.Subject)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, domain.ErrInvalidToken
+	}
+
+	sessionID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, nil, nil, domain.ErrInvalidToken
 	}
 
-	exists, err := s.tokenCache.TokenExists(ctx, token)
-	if err != nil || !exists {
-		return nil, nil, errors.New("token invalid or expired")
+	// Revoked sessions are gone from the cache, along with every token issued for them
+	session, err := s.tokenCache.GetSession(ctx, sessionID)
+	if err != nil || session.UserID != userID {
+		return nil, nil, nil, domain.ErrInvalidToken
 	}
 
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+
+	// Tokens issued before a role change must not keep the old permissions
+	if claims.Role != string(user.Type) {
+		return nil, nil, nil, domain.ErrInvalidToken
 	}
 
-	var profile interface{}
-	switch user.Type {
-	case domain.UserTypeRestaurant:
-		profile, err = s.restaurantRepo.GetByUserID(ctx, user.ID)
-	case domain.UserTypeVolunteer:
-		profile, err = s.volunteerRepo.GetByUserID(ctx, user.ID)
+	if user.IsSuspended() {
+		return nil, nil, nil, domain.ErrAccountSuspended
 	}
 
+	// Impersonated sessions end as soon as their administrator loses the role
+	if session.IsImpersonated() {
+		admin, err := s.userRepo.GetByID(ctx, *session.ImpersonatorID)
+		if err != nil || admin.Type != domain.UserTypeAdmin || admin.IsSuspended() {
+			_ = s.tokenCache.InvalidateSession(ctx, user.ID, session.ID)
+			return nil, nil, nil, domain.ErrInvalidToken
+		}
+	}
+
+	profile, err := profileOf(ctx, s.restaurantRepo, s.volunteerRepo, user)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return user, profile, nil
+	if now := time.Now(); now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		_ = s.tokenCache.TouchSession(ctx, session.ID, now)
+		session.LastSeenAt = now
+	}
+
+	return user, profile, session, nil
 }
 
-func (s *authService) RefreshToken(ctx context.Context, token string) (*domain.AuthResponse, domain.Token, error) {
-	user, _, err := s.ValidateToken(ctx, token)
+// RefreshToken exchanges a refresh token for a new access token and rotates the refresh token
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*domain.AuthResponse, domain.TokenPair, error) {
+	session, err := s.sessionForRefreshToken(ctx, refreshToken)
 	if err != nil {
-		return nil, domain.Token(""), err
+		return nil, domain.TokenPair{}, err
 	}
 
-	if err := s.tokenCache.InvalidateToken(ctx, user.ID); err != nil {
-		return nil, domain.Token(""), err
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, domain.TokenPair{}, domain.ErrInvalidRefreshToken
 	}
 
-	newToken, exp, err := s.jwtService.GenerateToken(user.ID.String())
-	if err != nil {
-		return nil, domain.Token(""), err
+	if user.IsSuspended() {
+		return nil, domain.TokenPair{}, domain.ErrAccountSuspended
 	}
 
-	if err := s.tokenCache.StoreToken(ctx, user.ID, newToken, time.Until(exp)); err != nil {
-		return nil, domain.Token(""), err
+	session.LastSeenAt = time.Now()
+	tokens, exp, err := s.issueTokens(ctx, user, session)
+	if err != nil {
+		return nil, domain.TokenPair{}, err
 	}
 
 	return &domain.AuthResponse{
-		ExpiresAt: exp,
-		User:      *user,
-	}, domain.Token(""), nil
+		ExpiresAt:        exp,
+		RefreshExpiresAt: session.ExpiresAt,
+		User:             *user,
+	}, tokens, nil
 }
 
-func (s *authService) Logout(ctx context.Context, token string) error {
-	claims, err := s.jwtService.ValidateToken(token)
+// Logout ends the session of the refresh token, other devices stay signed in
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	session, err := s.sessionForRefreshToken(ctx, refreshToken)
 	if err != nil {
 		return err
 	}
 
-	userID, err := uuid.Parse(claims.Subject)
+	if err := s.tokenCache.InvalidateSession(ctx, session.UserID, session.ID); err != nil {
+		return err
+	}
+
+	_ = auditAccount(ctx, s.auditRepo, nil, domain.AuditActionLogout, session.UserID, nil)
+
+	return nil
+}
+
+// ListSessions returns the sessions the user is signed in with, most recently used first
+func (s *authService) ListSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	return s.tokenCache.ListSessions(ctx, uid)
+}
+
+// RevokeSession signs one of the user's devices out
+func (s *authService) RevokeSession(ctx context.Context, userID string, sessionID string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return invalidID("user", err)
+	}
+
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		return invalidID("session", err)
+	}
+
+	session, err := s.tokenCache.GetSession(ctx, sid)
+	if err != nil || session.UserID != uid {
+		return domain.ErrSessionNotFound
+	}
+
+	return s.tokenCache.InvalidateSession(ctx, uid, sid)
+}
+
+// RevokeOtherSessions signs the user out on every device but the current one
+func (s *authService) RevokeOtherSessions(ctx context.Context, userID string, currentSessionID string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return invalidID("user", err)
+	}
+
+	return revokeOtherSessions(ctx, s.tokenCache, uid, currentSessionID)
+}
+
+func revokeOtherSessions(ctx context.Context, tokenCache ports.TokenCache, userID uuid.UUID, currentSessionID string) error {
+	sessions, err := tokenCache.ListSessions(ctx, userID)
 	if err != nil {
 		return err
 	}
 
-	return s.tokenCache.InvalidateToken(ctx, userID)
+	for _, session := range sessions {
+		if session.ID.String() == currentSessionID {
+			continue
+		}
+		if err := tokenCache.InvalidateSession(ctx, userID, session.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// auditAccount records an action on the user's own account. Sign-ins and emailed links come
+// from nobody signed in, those are attributed to the user.
+func auditAccount(ctx context.Context, auditRepo ports.AuditLogRepository, tx interface{}, action domain.AuditAction, userID uuid.UUID, changes domain.AuditChanges) error {
+	entry := newAuditEntry(ctx, action, domain.AuditTargetUser, userID.String())
+	if entry.ActorID == nil {
+		entry.ActorID = &userID
+	}
+	entry.Changes = changes
+	return auditRepo.Create(ctx, tx, entry)
 }
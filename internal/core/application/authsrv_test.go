@@ -1 +1,580 @@
 package application_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/application"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/jwt"
+	password_util "github.com/SOU9OUR-DCF/dcf-backend.git/pkg/password"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/token"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testEmail    = "ana@example.com"
+	testPassword = "correct horse battery staple"
+)
+
+// The fakes keep their state in memory and implement only what the tested paths use, the
+// embedded interfaces panic on anything else
+
+type fakeTxManager struct {
+	ports.TransactionManager
+}
+
+func (m fakeTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx interface{}) error) error {
+	return fn(ctx, nil)
+}
+
+type fakeUserRepo struct {
+	ports.UserRepository
+	mu    sync.Mutex
+	users map[uuid.UUID]domain.User
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return &user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, tx interface{}, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = *user
+	return nil
+}
+
+// fakeRestaurantRepo knows no memberships
+type fakeRestaurantRepo struct {
+	ports.RestaurantRepository
+}
+
+func (r fakeRestaurantRepo) GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.Restaurant, error) {
+	return nil, domain.ErrNoMembership
+}
+
+type fakeAttemptRepo struct {
+	ports.LoginAttemptRepository
+}
+
+func (r fakeAttemptRepo) Create(ctx context.Context, tx interface{}, attempt *domain.LoginAttempt) error {
+	return nil
+}
+
+type fakeAuditRepo struct {
+	ports.AuditLogRepository
+	mu      sync.Mutex
+	actions []domain.AuditAction
+}
+
+func (r *fakeAuditRepo) Create(ctx context.Context, tx interface{}, entry *domain.AuditLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.actions = append(r.actions, entry.Action)
+	return nil
+}
+
+// fakeTokenCache stores copies, like the Redis cache that serializes sessions
+type fakeTokenCache struct {
+	ports.TokenCache
+	mu       sync.Mutex
+	sessions map[uuid.UUID]domain.Session
+}
+
+func (c *fakeTokenCache) StoreSession(ctx context.Context, session *domain.Session, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stored := *session
+	stored.RotatedTokenHashes = append([]string(nil), session.RotatedTokenHashes...)
+	c.sessions[session.ID] = stored
+	return nil
+}
+
+func (c *fakeTokenCache) GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	session, ok := c.sessions[sessionID]
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	session.RotatedTokenHashes = append([]string(nil), session.RotatedTokenHashes...)
+	return &session, nil
+}
+
+func (c *fakeTokenCache) RotateRefreshToken(ctx context.Context, session *domain.Session, previousHash string, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current, ok := c.sessions[session.ID]
+	if !ok || current.RefreshTokenHash != previousHash {
+		return domain.ErrRefreshTokenReused
+	}
+	stored := *session
+	stored.RotatedTokenHashes = append(append([]string(nil), current.RotatedTokenHashes...), previousHash)
+	c.sessions[session.ID] = stored
+	return nil
+}
+
+func (c *fakeTokenCache) InvalidateUserSessions(ctx context.Context, userID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, session := range c.sessions {
+		if session.UserID == userID {
+			delete(c.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (c *fakeTokenCache) InvalidateSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sessions, sessionID)
+	return nil
+}
+
+// fakeTokenStore keeps every token until it is consumed
+type fakeTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]uuid.UUID
+}
+
+func (s *fakeTokenStore) Store(ctx context.Context, purpose string, tokenHash string, subjectID uuid.UUID, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[purpose+":"+tokenHash] = subjectID
+	return nil
+}
+
+func (s *fakeTokenStore) Consume(ctx context.Context, purpose string, tokenHash string) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subjectID, ok := s.tokens[purpose+":"+tokenHash]
+	if !ok {
+		return uuid.Nil, errors.New("token not found")
+	}
+	delete(s.tokens, purpose+":"+tokenHash)
+	return subjectID, nil
+}
+
+// fakeThrottle allows everything
+type fakeThrottle struct{}
+
+func (fakeThrottle) Allow(ctx context.Context, key string, interval time.Duration) (bool, error) {
+	return true, nil
+}
+
+// fakeAttemptCounter keeps the time of every attempt and counts those within the window
+type fakeAttemptCounter struct {
+	mu       sync.Mutex
+	attempts map[string][]time.Time
+}
+
+func (c *fakeAttemptCounter) Record(ctx context.Context, key string, window time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts[key] = append(c.attempts[key], time.Now())
+	return nil
+}
+
+func (c *fakeAttemptCounter) Recent(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count, latest := 0, time.Time{}
+	for _, at := range c.attempts[key] {
+		if time.Since(at) < window {
+			count++
+		}
+		if at.After(latest) {
+			latest = at
+		}
+	}
+	if count == 0 {
+		return 0, time.Time{}, nil
+	}
+	return count, latest, nil
+}
+
+func (c *fakeAttemptCounter) Reset(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.attempts, key)
+	return nil
+}
+
+// age moves every attempt recorded so far back in time
+func (c *fakeAttemptCounter) age(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, times := range c.attempts {
+		for i := range times {
+			times[i] = times[i].Add(-d)
+		}
+		c.attempts[key] = times
+	}
+}
+
+type fakeMailer struct {
+	mu     sync.Mutex
+	sent   []string
+	bodies []string
+}
+
+func (m *fakeMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, to)
+	m.bodies = append(m.bodies, body)
+	return nil
+}
+
+type authFixture struct {
+	service  ports.AuthService
+	accounts ports.AccountService
+	user     *domain.User
+	users    *fakeUserRepo
+	audit    *fakeAuditRepo
+	sessions *fakeTokenCache
+	attempts *fakeAttemptCounter
+	mailer   *fakeMailer
+}
+
+func newAuthFixture(t *testing.T, limits application.LoginLimits) *authFixture {
+	t.Helper()
+
+	hash, err := password_util.Hash(testPassword)
+	require.NoError(t, err)
+	user := &domain.User{ID: uuid.New(), Username: "ana", Email: testEmail, Password: hash, Type: domain.UserTypeRegular}
+
+	keys, err := jwt.LoadKeySet(t.TempDir())
+	require.NoError(t, err)
+
+	f := &authFixture{
+		user:     user,
+		users:    &fakeUserRepo{users: map[uuid.UUID]domain.User{user.ID: *user}},
+		audit:    &fakeAuditRepo{},
+		sessions: &fakeTokenCache{sessions: map[uuid.UUID]domain.Session{}},
+		attempts: &fakeAttemptCounter{attempts: map[string][]time.Time{}},
+		mailer:   &fakeMailer{},
+	}
+	tokens := &fakeTokenStore{tokens: map[string]uuid.UUID{}}
+	lockout := application.NewLockoutService(fakeTxManager{}, f.users, fakeAttemptRepo{}, f.audit, tokens, f.attempts, limits, f.mailer, "https://app.example.com")
+	twoFactor := application.NewTwoFactorService(fakeTxManager{}, f.users, nil, nil, nil, f.audit, tokens, fakeThrottle{}, lockout, f.mailer)
+	f.accounts = application.NewAccountService(fakeTxManager{}, f.users, nil, f.audit, f.sessions, tokens, fakeThrottle{}, f.mailer, "https://app.example.com")
+	f.service = application.NewAuthService(
+		fakeTxManager{}, f.users, fakeRestaurantRepo{}, nil, nil, nil, nil, nil, f.audit, nil,
+		f.sessions, lockout, twoFactor, f.accounts, jwt.NewService(keys, time.Minute), time.Hour,
+	)
+	return f
+}
+
+// openSession stores a session whose refresh token is "<session ID>.<secret>"
+func (f *authFixture) openSession(t *testing.T, secret string) (uuid.UUID, string) {
+	t.Helper()
+
+	session := &domain.Session{
+		ID:               uuid.New(),
+		UserID:           f.user.ID,
+		CreatedAt:        time.Now(),
+		LastSeenAt:       time.Now(),
+		ExpiresAt:        time.Now().Add(time.Hour),
+		RefreshTokenHash: token.Hash(secret),
+	}
+	require.NoError(t, f.sessions.StoreSession(context.Background(), session, time.Hour))
+	return session.ID, session.ID.String() + "." + secret
+}
+
+// lastToken returns the token of the link in the latest email
+func (f *authFixture) lastToken(t *testing.T) string {
+	t.Helper()
+
+	f.mailer.mu.Lock()
+	defer f.mailer.mu.Unlock()
+	require.NotEmpty(t, f.mailer.bodies)
+	match := regexp.MustCompile(`token=(\S+)`).FindStringSubmatch(f.mailer.bodies[len(f.mailer.bodies)-1])
+	require.Len(t, match, 2)
+	return match[1]
+}
+
+func (f *authFixture) login(ip, email, password string) error {
+	_, _, _, err := f.service.Login(context.Background(), domain.LoginRequest{Email: email, Password: password}, domain.DeviceInfo{IPAddress: ip})
+	return err
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t, application.LoginLimits{})
+	sessionID, first := f.openSession(t, "first-secret")
+
+	_, tokens, err := f.service.RefreshToken(ctx, first)
+	require.NoError(t, err)
+	second := tokens.RefreshToken.String()
+	assert.NotEqual(t, first, second)
+	assert.True(t, strings.HasPrefix(second, sessionID.String()+"."), "the session keeps its ID")
+	assert.NotEmpty(t, tokens.AccessToken.String())
+
+	_, tokens, err = f.service.RefreshToken(ctx, second)
+	require.NoError(t, err)
+	third := tokens.RefreshToken.String()
+
+	session, err := f.sessions.GetSession(ctx, sessionID)
+	require.NoError(t, err)
+	assert.Len(t, session.RotatedTokenHashes, 2)
+
+	// A rotated token coming back means it leaked, the whole session goes
+	_, _, err = f.service.RefreshToken(ctx, first)
+	assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+
+	_, _, err = f.service.RefreshToken(ctx, third)
+	assert.ErrorIs(t, err, domain.ErrInvalidRefreshToken)
+}
+
+func TestRefreshTokenConcurrently(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t, application.LoginLimits{})
+	sessionID, current := f.openSession(t, "current-secret")
+
+	const n = 10
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, errs[i] = f.service.RefreshToken(ctx, current)
+		}(i)
+	}
+	wg.Wait()
+
+	// Only one request rotates the token, any other used it after that and revoked the session
+	refreshed := 0
+	for _, err := range errs {
+		if err == nil {
+			refreshed++
+			continue
+		}
+		assert.True(t, errors.Is(err, domain.ErrRefreshTokenReused) || errors.Is(err, domain.ErrInvalidRefreshToken), err)
+	}
+	assert.Equal(t, 1, refreshed)
+
+	_, err := f.sessions.GetSession(ctx, sessionID)
+	assert.Error(t, err)
+}
+
+func TestRefreshTokenRejects(t *testing.T) {
+	tests := []struct {
+		name         string
+		refreshToken func(sessionID uuid.UUID, current string) string
+		suspended    bool
+		wantErr      error
+		wantSession  bool
+	}{
+		{"not a refresh token", func(uuid.UUID, string) string { return "secret" }, false, domain.ErrInvalidRefreshToken, true},
+		{"malformed session ID", func(uuid.UUID, string) string { return "session.secret" }, false, domain.ErrInvalidRefreshToken, true},
+		{"unknown session", func(uuid.UUID, string) string { return uuid.NewString() + ".current-secret" }, false, domain.ErrInvalidRefreshToken, true},
+		{"secret never issued", func(id uuid.UUID, _ string) string { return id.String() + ".guessed-secret" }, false, domain.ErrInvalidRefreshToken, true},
+		{"session ID alone", func(id uuid.UUID, _ string) string { return id.String() + "." }, false, domain.ErrInvalidRefreshToken, true},
+		{"suspended user", func(_ uuid.UUID, current string) string { return current }, true, domain.ErrAccountSuspended, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newAuthFixture(t, application.LoginLimits{})
+			if tt.suspended {
+				suspendedAt := time.Now()
+				f.user.SuspendedAt = &suspendedAt
+				require.NoError(t, f.users.Update(ctx, nil, f.user))
+			}
+			sessionID, current := f.openSession(t, "current-secret")
+
+			_, _, err := f.service.RefreshToken(ctx, tt.refreshToken(sessionID, current))
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			// Only the reuse of a rotated token revokes the session
+			_, err = f.sessions.GetSession(ctx, sessionID)
+			assert.Equal(t, tt.wantSession, err == nil)
+		})
+	}
+}
+
+func TestLoginLocksAccount(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t, application.LoginLimits{
+		Window:             15 * time.Minute,
+		MaxAccountFailures: 3,
+		LockoutDuration:    30 * time.Minute,
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+	}
+
+	user, err := f.users.GetByID(ctx, f.user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, user.LockedUntil)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), *user.LockedUntil, 5*time.Second)
+	assert.Equal(t, []string{testEmail}, f.mailer.sent)
+	assert.Contains(t, f.audit.actions, domain.AuditActionAccountLocked)
+
+	// The right password does not help while the account is locked
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, testPassword), domain.ErrAccountLocked)
+}
+
+func TestLoginFailuresSlideOutOfWindow(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t, application.LoginLimits{
+		Window:             15 * time.Minute,
+		MaxAccountFailures: 3,
+		LockoutDuration:    30 * time.Minute,
+	})
+
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+
+	// Half of the window later the first two failures still count, a full window later
+	// they do not
+	f.attempts.age(10 * time.Minute)
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+	user, err := f.users.GetByID(ctx, f.user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, user.LockedUntil, "three failures within 15 minutes lock the account")
+
+	f = newAuthFixture(t, application.LoginLimits{
+		Window:             15 * time.Minute,
+		MaxAccountFailures: 3,
+		LockoutDuration:    30 * time.Minute,
+	})
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+	f.attempts.age(16 * time.Minute)
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+	user, err = f.users.GetByID(ctx, f.user.ID)
+	require.NoError(t, err)
+	assert.Nil(t, user.LockedUntil, "failures older than the window are forgotten")
+}
+
+func TestLoginDelay(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    int
+		ago         time.Duration
+		wantLimited bool
+	}{
+		{"below the threshold", 1, 0, false},
+		{"at the threshold", 2, 0, true},
+		{"base delay passed", 2, 61 * time.Second, false},
+		{"delay doubles", 3, 90 * time.Second, true},
+		{"doubled delay passed", 3, 121 * time.Second, false},
+		{"delay is capped", 10, 4*time.Minute + time.Second, false},
+		{"capped delay not passed", 10, 3 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t, application.LoginLimits{
+				Window:     time.Hour,
+				DelayAfter: 2,
+				BaseDelay:  time.Minute,
+				MaxDelay:   4 * time.Minute,
+			})
+
+			// Failures from many addresses, the delay is per account
+			for i := 0; i < tt.failures; i++ {
+				f.attempts.age(time.Second)
+				require.NoError(t, f.attempts.Record(context.Background(), "login:account:"+testEmail, time.Hour))
+			}
+			f.attempts.age(tt.ago)
+
+			err := f.login("203.0.113.7", testEmail, testPassword)
+			if !tt.wantLimited {
+				assert.NoError(t, err)
+				return
+			}
+
+			var domainErr *domain.Error
+			require.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, domain.ErrorKindRateLimited, domainErr.Kind)
+			assert.Equal(t, domain.ErrCodeTooManyAttempts, domainErr.Code)
+		})
+	}
+}
+
+func TestLoginLimitsAddress(t *testing.T) {
+	f := newAuthFixture(t, application.LoginLimits{
+		Window:        15 * time.Minute,
+		MaxIPFailures: 3,
+	})
+
+	// Guessing across many accounts from one address
+	for _, email := range []string{"bob@example.com", "carla@example.com", "dan@example.com"} {
+		assert.ErrorIs(t, f.login("203.0.113.7", email, "password"), domain.ErrInvalidCredentials)
+	}
+
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, testPassword), domain.ErrTooManyFailedSignIns)
+	assert.NoError(t, f.login("198.51.100.20", testEmail, testPassword))
+}
+
+func TestLoginResetsAccountFailures(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t, application.LoginLimits{
+		Window:             15 * time.Minute,
+		MaxAccountFailures: 3,
+		LockoutDuration:    30 * time.Minute,
+	})
+
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+	require.NoError(t, f.login("203.0.113.7", testEmail, testPassword))
+
+	failures, _, err := f.attempts.Recent(ctx, "login:account:"+testEmail, 15*time.Minute)
+	require.NoError(t, err)
+	assert.Zero(t, failures)
+
+	assert.ErrorIs(t, f.login("203.0.113.7", testEmail, "wrong password"), domain.ErrInvalidCredentials)
+	user, err := f.users.GetByID(ctx, f.user.ID)
+	require.NoError(t, err)
+	assert.Nil(t, user.LockedUntil)
+}
+
+func TestValidateTokenWithoutMembership(t *testing.T) {
+	ctx := context.Background()
+	f := newAuthFixture(t, application.LoginLimits{})
+
+	// Staff removed from their last restaurant keep their account
+	f.user.Type = domain.UserTypeRestaurant
+	require.NoError(t, f.users.Update(ctx, nil, f.user))
+	_, refreshToken := f.openSession(t, "current-secret")
+	_, tokens, err := f.service.RefreshToken(ctx, refreshToken)
+	require.NoError(t, err)
+
+	user, profile, _, err := f.service.ValidateToken(ctx, tokens.AccessToken.String())
+	require.NoError(t, err)
+	assert.Equal(t, f.user.ID, user.ID)
+	assert.Nil(t, profile)
+}
@@ -0,0 +1,12 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+)
+
+// invalidID is the error for an ID that does not parse as a UUID, name says which ID
+func invalidID(name string, err error) error {
+	return domain.NewValidationError(domain.ErrCodeInvalidID, fmt.Sprintf("invalid %s ID: %v", name, err))
+}
@@ -13,17 +13,29 @@ type eventService struct {
 	txManager      ports.TransactionManager
 	eventRepo      ports.EventRepository
 	restaurantRepo ports.RestaurantRepository
+	branchRepo     ports.BranchRepository
+	hostRepo       ports.EventHostRepository
+	mealLogRepo    ports.MealLogRepository
+	auditRepo      ports.AuditLogRepository
 }
 
 func NewEventService(
 	txManager ports.TransactionManager,
 	eventRepo ports.EventRepository,
 	restaurantRepo ports.RestaurantRepository,
+	branchRepo ports.BranchRepository,
+	hostRepo ports.EventHostRepository,
+	mealLogRepo ports.MealLogRepository,
+	auditRepo ports.AuditLogRepository,
 ) ports.EventService {
 	return &eventService{
 		txManager:      txManager,
 		eventRepo:      eventRepo,
 		restaurantRepo: restaurantRepo,
+		branchRepo:     branchRepo,
+		hostRepo:       hostRepo,
+		mealLogRepo:    mealLogRepo,
+		auditRepo:      auditRepo,
 	}
 }
 
@@ -31,6 +43,18 @@ func (s *eventService) CreateEvent(ctx context.Context, event *domain.Event) err
 	// Set initial status
 	event.Status = domain.EventStatusUpcoming
 
+	restaurant, err := s.restaurantRepo.GetByID(ctx, event.RestaurantID)
+	if err != nil {
+		return err
+	}
+	if !restaurant.IsBusinessEmailVerified() {
+		return domain.ErrBusinessEmailUnverified
+	}
+
+	if err := s.assignBranch(ctx, event); err != nil {
+		return err
+	}
+
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
 		// Create the event
 		if err := s.eventRepo.Create(ctx, tx, event); err != nil {
@@ -50,31 +74,45 @@ func (s *eventService) CreateEvent(ctx context.Context, event *domain.Event) err
 func (s *eventService) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
 	eventID, err := uuid.Parse(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid event ID: %w", err)
+		return nil, invalidID("event", err)
 	}
 
 	return s.eventRepo.GetByID(ctx, eventID)
 }
 
-func (s *eventService) GetUpcomingEvents(ctx context.Context, restaurantID string, limit, offset int) ([]*domain.Event, int, error) {
+func (s *eventService) GetUpcomingEvents(ctx context.Context, restaurantID string, branchID string, limit, offset int) ([]*domain.Event, int, error) {
 	rid, err := uuid.Parse(restaurantID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("invalid restaurant ID: %w", err)
+		return nil, 0, invalidID("restaurant", err)
 	}
 
-	return s.eventRepo.GetByRestaurantID(ctx, rid, string(domain.EventStatusUpcoming), limit, offset)
+	bid, err := parseOptionalID(branchID)
+	if err != nil {
+		return nil, 0, invalidID("branch", err)
+	}
+
+	return s.eventRepo.GetByRestaurantID(ctx, rid, bid, string(domain.EventStatusUpcoming), limit, offset)
 }
 
-func (s *eventService) GetTodayEvents(ctx context.Context, restaurantID string) ([]*domain.Event, error) {
+func (s *eventService) GetTodayEvents(ctx context.Context, restaurantID string, branchID string) ([]*domain.Event, error) {
 	rid, err := uuid.Parse(restaurantID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid restaurant ID: %w", err)
+		return nil, invalidID("restaurant", err)
+	}
+
+	bid, err := parseOptionalID(branchID)
+	if err != nil {
+		return nil, invalidID("branch", err)
 	}
 
-	return s.eventRepo.GetTodayEvents(ctx, rid)
+	return s.eventRepo.GetTodayEvents(ctx, rid, bid)
 }
 
 func (s *eventService) UpdateEvent(ctx context.Context, event *domain.Event) error {
+	if err := s.assignBranch(ctx, event); err != nil {
+		return err
+	}
+
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
 		return s.eventRepo.Update(ctx, tx, event)
 	})
@@ -83,18 +121,29 @@ func (s *eventService) UpdateEvent(ctx context.Context, event *domain.Event) err
 func (s *eventService) UpdateEventStatus(ctx context.Context, id string, status domain.EventStatus) error {
 	eventID, err := uuid.Parse(id)
 	if err != nil {
-		return fmt.Errorf("invalid event ID: %w", err)
+		return invalidID("event", err)
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return err
 	}
 
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
-		return s.eventRepo.UpdateStatus(ctx, tx, eventID, string(status))
+		if err := s.eventRepo.UpdateStatus(ctx, tx, eventID, string(status)); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionEventStatusChanged, domain.AuditTargetEvent, event.ID.String())
+		entry.Changes = auditChange("status", event.Status, status)
+		return s.auditRepo.Create(ctx, tx, entry)
 	})
 }
 
 func (s *eventService) UpdateGuestCount(ctx context.Context, id string, count int) error {
 	eventID, err := uuid.Parse(id)
 	if err != nil {
-		return fmt.Errorf("invalid event ID: %w", err)
+		return invalidID("event", err)
 	}
 
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
@@ -102,38 +151,189 @@ func (s *eventService) UpdateGuestCount(ctx context.Context, id string, count in
 	})
 }
 
-func (s *eventService) UpdateMealsServed(ctx context.Context, id string, count int) error {
-	eventID, err := uuid.Parse(id)
+// UpdateMealsServed sets the meals the host served at the event by appending the
+// difference to the meal log, the meals of the other hosts are left as they are
+func (s *eventService) UpdateMealsServed(ctx context.Context, id string, hostID string, recordedBy string, count int) error {
+	if count < 0 {
+		return domain.ErrNegativeMealsServed
+	}
+
+	_, err := s.appendMealEntry(ctx, domain.AuditActionMealsServedUpdated, id, hostID, recordedBy, nil, func(current int) (int, string) {
+		return count - current, fmt.Sprintf("total adjusted from %d to %d", current, count)
+	})
+	return err
+}
+
+func (s *eventService) RecordMeals(ctx context.Context, eventID string, hostID string, recordedBy string, count int, note string) (*domain.MealLogEntry, error) {
+	if count <= 0 {
+		return nil, domain.ErrInvalidMealCount
+	}
+
+	return s.appendMealEntry(ctx, domain.AuditActionMealsRecorded, eventID, hostID, recordedBy, nil, func(int) (int, string) {
+		return count, note
+	})
+}
+
+func (s *eventService) CorrectMealEntry(ctx context.Context, eventID string, entryID string, hostID string, recordedBy string, note string) (*domain.MealLogEntry, error) {
+	eid, err := uuid.Parse(eventID)
 	if err != nil {
-		return fmt.Errorf("invalid event ID: %w", err)
+		return nil, invalidID("event", err)
 	}
 
-	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
-		// Update event meals served
-		if err := s.eventRepo.UpdateMealsServed(ctx, tx, eventID, count); err != nil {
+	id, err := uuid.Parse(entryID)
+	if err != nil {
+		return nil, invalidID("meal log entry", err)
+	}
+
+	rid, err := uuid.Parse(hostID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	entry, err := s.mealLogRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.EventID != eid {
+		return nil, domain.ErrMealEntryNotInEvent
+	}
+
+	// A host only takes back the meals credited to itself
+	if entry.RestaurantID != rid {
+		return nil, domain.ErrEntryOfOtherHost
+	}
+
+	if entry.IsCorrection() {
+		return nil, domain.ErrCorrectionOfCorrection
+	}
+
+	// The correction is taken off the host the entry was credited to
+	return s.appendMealEntry(ctx, domain.AuditActionMealEntryCorrected, entry.EventID.String(), entry.RestaurantID.String(), recordedBy, &entry.ID, func(int) (int, string) {
+		return -entry.Count, note
+	})
+}
+
+func (s *eventService) GetMealLog(ctx context.Context, eventID string) ([]*domain.MealLogEntry, error) {
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, invalidID("event", err)
+	}
+
+	return s.mealLogRepo.GetByEventID(ctx, eid)
+}
+
+// appendMealEntry adds an entry to the meal log and records the action that caused it in
+// the audit log. The event is locked while entryFor turns the meals credited to the host
+// into the count of the entry, so concurrent changes are applied one after the other. No
+// entry is added when the count is zero, and none that would leave the host below zero.
+func (s *eventService) appendMealEntry(ctx context.Context, action domain.AuditAction, eventID string, hostID string, recordedBy string, correctsEntryID *uuid.UUID, entryFor func(current int) (count int, note string)) (*domain.MealLogEntry, error) {
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, invalidID("event", err)
+	}
+
+	rid, err := uuid.Parse(hostID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	uid, err := uuid.Parse(recordedBy)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, eid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !event.HostCan(rid, domain.PermissionRecordMeals) {
+		return nil, domain.ErrCannotRecordMeals
+	}
+
+	var entry *domain.MealLogEntry
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if _, err := s.eventRepo.GetByIDForUpdate(ctx, tx, event.ID); err != nil {
 			return err
 		}
 
-		// Get the event to update restaurant stats
-		event, err := s.eventRepo.GetByID(ctx, eventID)
+		hostTotal, err := s.mealLogRepo.SumByEventAndRestaurantID(ctx, tx, event.ID, rid)
 		if err != nil {
 			return err
 		}
 
-		// Update restaurant total meals served
-		restaurant, err := s.restaurantRepo.GetByID(ctx, event.RestaurantID)
+		count, note := entryFor(hostTotal)
+		if count == 0 {
+			return nil
+		}
+		if hostTotal+count < 0 {
+			return domain.ErrNegativeMealsServed
+		}
+
+		current, err := s.mealLogRepo.SumByEventID(ctx, tx, event.ID)
 		if err != nil {
 			return err
 		}
 
-		return s.restaurantRepo.UpdateStats(ctx, tx, restaurant.ID, restaurant.TotalEvents, restaurant.MealsServed+count, restaurant.Rating)
+		entry = &domain.MealLogEntry{
+			EventID:         event.ID,
+			RestaurantID:    rid,
+			RecordedBy:      uid,
+			Count:           count,
+			Note:            note,
+			CorrectsEntryID: correctsEntryID,
+		}
+		if err := s.mealLogRepo.Create(ctx, tx, entry); err != nil {
+			return err
+		}
+
+		auditEntry := newAuditEntry(ctx, action, domain.AuditTargetEvent, event.ID.String())
+		auditEntry.Detail = fmt.Sprintf("meal log entry %s", entry.ID)
+		auditEntry.Changes = auditChange("meals_served", current, current+count)
+		if err := s.auditRepo.Create(ctx, tx, auditEntry); err != nil {
+			return err
+		}
+
+		return s.syncMealTotals(ctx, tx, event.ID, rid)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// syncMealTotals recomputes the denormalized meal counters from the log, in the
+// transaction that changed it. The host is locked so its total includes the entries other
+// events are adding at the same time.
+func (s *eventService) syncMealTotals(ctx context.Context, tx interface{}, eventID, restaurantID uuid.UUID) error {
+	restaurant, err := s.restaurantRepo.GetByIDForUpdate(ctx, tx, restaurantID)
+	if err != nil {
+		return err
+	}
+
+	eventTotal, err := s.mealLogRepo.SumByEventID(ctx, tx, eventID)
+	if err != nil {
+		return err
+	}
+
+	restaurantTotal, err := s.mealLogRepo.SumByRestaurantID(ctx, tx, restaurantID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.eventRepo.UpdateMealsServed(ctx, tx, eventID, eventTotal); err != nil {
+		return err
+	}
+
+	return s.restaurantRepo.UpdateStats(ctx, tx, restaurant.ID, restaurant.TotalEvents, restaurantTotal, restaurant.Rating)
 }
 
 func (s *eventService) DeleteEvent(ctx context.Context, id string) error {
 	eventID, err := uuid.Parse(id)
 	if err != nil {
-		return fmt.Errorf("invalid event ID: %w", err)
+		return invalidID("event", err)
 	}
 
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
@@ -148,12 +348,142 @@ func (s *eventService) DeleteEvent(ctx context.Context, id string) error {
 			return err
 		}
 
-		// Update restaurant stats
-		restaurant, err := s.restaurantRepo.GetByID(ctx, event.RestaurantID)
-		if err != nil {
+		// Update the stats of every host
+		for _, host := range event.Hosts {
+			if err := s.addHostedEvents(ctx, tx, host.RestaurantID, -1); err != nil {
+				return err
+			}
+		}
+
+		return s.addHostedEvents(ctx, tx, event.RestaurantID, -1)
+	})
+}
+
+func (s *eventService) GetHosts(ctx context.Context, eventID string) ([]*domain.EventHost, error) {
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, invalidID("event", err)
+	}
+
+	return s.hostRepo.GetByEventID(ctx, eid)
+}
+
+func (s *eventService) GetHost(ctx context.Context, id string) (*domain.EventHost, error) {
+	hid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("host", err)
+	}
+
+	return s.hostRepo.GetByID(ctx, hid)
+}
+
+// AddHost makes another organization co-host of the event, the event counts towards
+// its stats from then on
+func (s *eventService) AddHost(ctx context.Context, host *domain.EventHost) error {
+	event, err := s.eventRepo.GetByID(ctx, host.EventID)
+	if err != nil {
+		return err
+	}
+
+	if host.RestaurantID == event.RestaurantID {
+		return domain.ErrAlreadyHost
+	}
+
+	for _, h := range event.Hosts {
+		if h.RestaurantID == host.RestaurantID {
+			return domain.ErrAlreadyCoHost
+		}
+	}
+
+	if _, err := s.restaurantRepo.GetByID(ctx, host.RestaurantID); err != nil {
+		return domain.ErrOrganizationNotFound
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.hostRepo.Create(ctx, tx, host); err != nil {
 			return err
 		}
 
-		return s.restaurantRepo.UpdateStats(ctx, tx, restaurant.ID, restaurant.TotalEvents-1, restaurant.MealsServed, restaurant.Rating)
+		return s.addHostedEvents(ctx, tx, host.RestaurantID, 1)
 	})
 }
+
+func (s *eventService) UpdateHost(ctx context.Context, host *domain.EventHost) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.hostRepo.Update(ctx, tx, host)
+	})
+}
+
+// RemoveHost ends a co-hosting, the meals the co-host recorded stay credited to it
+func (s *eventService) RemoveHost(ctx context.Context, id string) error {
+	hid, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("host", err)
+	}
+
+	host, err := s.hostRepo.GetByID(ctx, hid)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.hostRepo.Delete(ctx, tx, hid); err != nil {
+			return err
+		}
+
+		return s.addHostedEvents(ctx, tx, host.RestaurantID, -1)
+	})
+}
+
+// addHostedEvents adjusts the number of events a restaurant hosts
+func (s *eventService) addHostedEvents(ctx context.Context, tx interface{}, restaurantID uuid.UUID, delta int) error {
+	restaurant, err := s.restaurantRepo.GetByID(ctx, restaurantID)
+	if err != nil {
+		return err
+	}
+
+	return s.restaurantRepo.UpdateStats(ctx, tx, restaurant.ID, restaurant.TotalEvents+delta, restaurant.MealsServed, restaurant.Rating)
+}
+
+// assignBranch checks the event's branch belongs to its restaurant. Events without a
+// branch are placed in the restaurant's only branch, restaurants with several branches
+// have to pick one.
+func (s *eventService) assignBranch(ctx context.Context, event *domain.Event) error {
+	if event.BranchID == nil {
+		branches, err := s.branchRepo.GetByRestaurantID(ctx, event.RestaurantID)
+		if err != nil {
+			return err
+		}
+
+		if len(branches) != 1 {
+			return domain.ErrBranchRequired
+		}
+
+		event.BranchID = &branches[0].ID
+	}
+
+	branch, err := s.branchRepo.GetByID(ctx, *event.BranchID)
+	if err != nil || branch.RestaurantID != event.RestaurantID {
+		return domain.ErrBranchNotInRestaurant
+	}
+
+	// Default the location to the branch address
+	if event.Location == "" {
+		event.Location = branch.Address
+	}
+
+	return nil
+}
+
+// parseOptionalID parses an ID that may be left empty
+func parseOptionalID(id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/jwt"
+)
+
+type impersonationService struct {
+	userRepo       ports.UserRepository
+	restaurantRepo ports.RestaurantRepository
+	volunteerRepo  ports.VolunteerRepository
+	auditRepo      ports.AuditLogRepository
+	tokenCache     ports.TokenCache
+	jwtService     *jwt.Service
+}
+
+func NewImpersonationService(
+	userRepo ports.UserRepository,
+	restaurantRepo ports.RestaurantRepository,
+	volunteerRepo ports.VolunteerRepository,
+	auditRepo ports.AuditLogRepository,
+	tokenCache ports.TokenCache,
+	jwtService *jwt.Service,
+) ports.ImpersonationService {
+	return &impersonationService{
+		userRepo:       userRepo,
+		restaurantRepo: restaurantRepo,
+		volunteerRepo:  volunteerRepo,
+		auditRepo:      auditRepo,
+		tokenCache:     tokenCache,
+		jwtService:     jwtService,
+	}
+}
+
+// StartImpersonation opens a session as the target user for an administrator, so support
+// can see what the user sees. It only gets an access token, so it ends when the token
+// expires and cannot be refreshed.
+func (s *impersonationService) StartImpersonation(ctx context.Context, adminID string, targetID string, req domain.ImpersonationRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error) {
+	aid, err := uuid.Parse(adminID)
+	if err != nil {
+		return nil, domain.TokenPair{}, invalidID("user", err)
+	}
+
+	tid, err := uuid.Parse(targetID)
+	if err != nil {
+		return nil, domain.TokenPair{}, invalidID("user", err)
+	}
+
+	target, err := s.userRepo.GetByID(ctx, tid)
+	if err != nil {
+		return nil, domain.TokenPair{}, err
+	}
+
+	if target.Type == domain.UserTypeAdmin {
+		return nil, domain.TokenPair{}, domain.ErrAdminNotImpersonable
+	}
+
+	if target.IsSuspended() {
+		return nil, domain.TokenPair{}, domain.ErrSuspendedNotImpersonable
+	}
+
+	profile, err := profileOf(ctx, s.restaurantRepo, s.volunteerRepo, target)
+	if err != nil {
+		return nil, domain.TokenPair{}, err
+	}
+
+	now := time.Now()
+	session := &domain.Session{
+		ID:                  uuid.New(),
+		UserID:              target.ID,
+		UserAgent:           device.UserAgent,
+		IPAddress:           device.IPAddress,
+		CreatedAt:           now,
+		LastSeenAt:          now,
+		ImpersonatorID:      &aid,
+		ImpersonationReason: req.Reason,
+	}
+
+	accessToken, exp, err := s.jwtService.GenerateToken(target.ID.String(), session.ID.String(), string(target.Type))
+	if err != nil {
+		return nil, domain.TokenPair{}, err
+	}
+	session.ExpiresAt = exp
+
+	// No impersonation without its record
+	entry := newAuditEntry(ctx, domain.AuditActionImpersonationStarted, domain.AuditTargetUser, target.ID.String())
+	entry.ActorID = &aid
+	entry.Detail = req.Reason
+	if err := s.auditRepo.Create(ctx, nil, entry); err != nil {
+		return nil, domain.TokenPair{}, err
+	}
+
+	if err := s.tokenCache.StoreSession(ctx, session, time.Until(exp)); err != nil {
+		return nil, domain.TokenPair{}, err
+	}
+
+	return &domain.AuthResponse{
+		ExpiresAt:        exp,
+		RefreshExpiresAt: exp,
+		User:             *target,
+		Profile:          profile,
+		ImpersonatorID:   &aid,
+	}, domain.TokenPair{AccessToken: domain.NewToken(accessToken)}, nil
+}
+
+// EndImpersonation closes the impersonated session the request was made with
+func (s *impersonationService) EndImpersonation(ctx context.Context, userID string, sessionID string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return invalidID("user", err)
+	}
+
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		return invalidID("session", err)
+	}
+
+	session, err := s.tokenCache.GetSession(ctx, sid)
+	if err != nil || session.UserID != uid {
+		return domain.ErrSessionNotFound
+	}
+
+	if !session.IsImpersonated() {
+		return domain.ErrNotImpersonating
+	}
+
+	if err := s.tokenCache.InvalidateSession(ctx, uid, sid); err != nil {
+		return err
+	}
+
+	entry := newAuditEntry(ctx, domain.AuditActionImpersonationEnded, domain.AuditTargetUser, uid.String())
+	entry.ActorID = session.ImpersonatorID
+	entry.ImpersonatorID = nil
+	_ = s.auditRepo.Create(ctx, nil, entry)
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// Items expiring within this window are reported as alerts on the restaurant stats
+const defaultExpiryAlertWindow = 72 * time.Hour
+
+type inventoryService struct {
+	txManager       ports.TransactionManager
+	inventoryRepo   ports.InventoryRepository
+	consumptionRepo ports.InventoryConsumptionRepository
+	eventRepo       ports.EventRepository
+}
+
+func NewInventoryService(
+	txManager ports.TransactionManager,
+	inventoryRepo ports.InventoryRepository,
+	consumptionRepo ports.InventoryConsumptionRepository,
+	eventRepo ports.EventRepository,
+) ports.InventoryService {
+	return &inventoryService{
+		txManager:       txManager,
+		inventoryRepo:   inventoryRepo,
+		consumptionRepo: consumptionRepo,
+		eventRepo:       eventRepo,
+	}
+}
+
+func (s *inventoryService) AddItem(ctx context.Context, item *domain.InventoryItem) error {
+	if item.Quantity < 0 {
+		return domain.ErrNegativeQuantity
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.inventoryRepo.Create(ctx, tx, item)
+	})
+}
+
+func (s *inventoryService) GetItem(ctx context.Context, id string) (*domain.InventoryItem, error) {
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("inventory item", err)
+	}
+
+	return s.inventoryRepo.GetByID(ctx, itemID)
+}
+
+func (s *inventoryService) GetInventory(ctx context.Context, restaurantID string) ([]*domain.InventoryItem, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	return s.inventoryRepo.GetByRestaurantID(ctx, rid)
+}
+
+func (s *inventoryService) UpdateItem(ctx context.Context, item *domain.InventoryItem) error {
+	if item.Quantity < 0 {
+		return domain.ErrNegativeQuantity
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.inventoryRepo.Update(ctx, tx, item)
+	})
+}
+
+func (s *inventoryService) DeleteItem(ctx context.Context, id string) error {
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("inventory item", err)
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.inventoryRepo.Delete(ctx, tx, itemID)
+	})
+}
+
+func (s *inventoryService) GetExpiringItems(ctx context.Context, restaurantID string, within time.Duration) ([]*domain.InventoryItem, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	if within <= 0 {
+		within = defaultExpiryAlertWindow
+	}
+
+	return s.inventoryRepo.GetExpiring(ctx, rid, time.Now().Add(within))
+}
+
+func (s *inventoryService) RecordConsumption(ctx context.Context, eventID string, itemID string, quantity float64) (*domain.InventoryConsumption, error) {
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, invalidID("event", err)
+	}
+
+	iid, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil, invalidID("inventory item", err)
+	}
+
+	if quantity <= 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, eid)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.inventoryRepo.GetByID(ctx, iid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ingredients can only be used by events of the restaurant that holds them
+	if item.RestaurantID != event.RestaurantID {
+		return nil, domain.ErrItemNotInEventRestaurant
+	}
+
+	if quantity > item.Quantity {
+		return nil, domain.NewConflictError(domain.ErrCodeInsufficientStock, fmt.Sprintf("only %.2f %s of %s left in stock", item.Quantity, item.Unit, item.Name))
+	}
+
+	consumption := &domain.InventoryConsumption{
+		InventoryItemID: item.ID,
+		EventID:         event.ID,
+		Quantity:        quantity,
+		UnitCost:        item.UnitCost,
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		// The check above may be outdated by now, the decrement only applies while enough is left
+		ok, err := s.inventoryRepo.DecrementQuantity(ctx, tx, item.ID, quantity)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return domain.NewConflictError(domain.ErrCodeInsufficientStock, fmt.Sprintf("not enough %s left in stock", item.Name))
+		}
+
+		return s.consumptionRepo.Create(ctx, tx, consumption)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Report the stock left after this consumption and any concurrent ones
+	if updated, err := s.inventoryRepo.GetByID(ctx, item.ID); err == nil {
+		item = updated
+	}
+
+	consumption.InventoryItem = *item
+	return consumption, nil
+}
+
+func (s *inventoryService) GetEventSummary(ctx context.Context, eventID string) (*domain.EventInventorySummary, error) {
+	eid, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, invalidID("event", err)
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, eid)
+	if err != nil {
+		return nil, err
+	}
+
+	consumptions, err := s.consumptionRepo.GetByEventID(ctx, eid)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizeEventInventory(event, consumptions), nil
+}
+
+// summarizeEventInventory aggregates the consumption records of an event per ingredient
+func summarizeEventInventory(event *domain.Event, consumptions []*domain.InventoryConsumption) *domain.EventInventorySummary {
+	summary := &domain.EventInventorySummary{
+		EventID:     event.ID,
+		Title:       event.Title,
+		MealsServed: event.MealsServed,
+		Ingredients: []domain.IngredientUsage{},
+	}
+
+	usageIndex := make(map[uuid.UUID]int)
+	for _, c := range consumptions {
+		cost := c.Quantity * c.UnitCost
+		summary.TotalCost += cost
+
+		idx, ok := usageIndex[c.InventoryItemID]
+		if !ok {
+			idx = len(summary.Ingredients)
+			usageIndex[c.InventoryItemID] = idx
+			summary.Ingredients = append(summary.Ingredients, domain.IngredientUsage{
+				InventoryItemID: c.InventoryItemID,
+				Name:            c.InventoryItem.Name,
+				Unit:            c.InventoryItem.Unit,
+				Donated:         c.InventoryItem.IsDonated(),
+			})
+		}
+
+		summary.Ingredients[idx].Quantity += c.Quantity
+		summary.Ingredients[idx].Cost += cost
+	}
+
+	if summary.MealsServed > 0 {
+		summary.CostPerMeal = summary.TotalCost / float64(summary.MealsServed)
+	}
+
+	return summary
+}
@@ -0,0 +1,212 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/token"
+)
+
+const accountUnlockPurpose = "account_unlock"
+
+// LoginLimits configures how failed sign-ins are limited, per account and per IP address,
+// over a sliding window
+type LoginLimits struct {
+	Window             time.Duration
+	MaxAccountFailures int
+	MaxIPFailures      int
+	LockoutDuration    time.Duration
+	DelayAfter         int
+	BaseDelay          time.Duration
+	MaxDelay           time.Duration
+}
+
+type lockoutService struct {
+	txManager   ports.TransactionManager
+	userRepo    ports.UserRepository
+	attemptRepo ports.LoginAttemptRepository
+	auditRepo   ports.AuditLogRepository
+	tokenStore  ports.OneTimeTokenStore
+	attempts    ports.AttemptCounter
+	limits      LoginLimits
+	mailer      ports.Mailer
+	appURL      string
+}
+
+func NewLockoutService(
+	txManager ports.TransactionManager,
+	userRepo ports.UserRepository,
+	attemptRepo ports.LoginAttemptRepository,
+	auditRepo ports.AuditLogRepository,
+	tokenStore ports.OneTimeTokenStore,
+	attempts ports.AttemptCounter,
+	limits LoginLimits,
+	mailer ports.Mailer,
+	appURL string,
+) ports.LockoutService {
+	return &lockoutService{
+		txManager:   txManager,
+		userRepo:    userRepo,
+		attemptRepo: attemptRepo,
+		auditRepo:   auditRepo,
+		tokenStore:  tokenStore,
+		attempts:    attempts,
+		limits:      limits,
+		mailer:      mailer,
+		appURL:      strings.TrimRight(appURL, "/"),
+	}
+}
+
+// UnlockAccount lifts the lockout of the account an unlock link was sent to
+func (s *lockoutService) UnlockAccount(ctx context.Context, rawToken string) error {
+	userID, err := s.tokenStore.Consume(ctx, accountUnlockPurpose, token.Hash(rawToken))
+	if err != nil {
+		return domain.ErrInvalidUnlockLink
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.ErrInvalidUnlockLink
+	}
+
+	user.LockedUntil = nil
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+		return auditAccount(ctx, s.auditRepo, tx, domain.AuditActionAccountUnlocked, user.ID, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.attempts.Reset(ctx, loginAccountKey(user.Email))
+}
+
+func loginAccountKey(email string) string {
+	return "login:account:" + strings.ToLower(email)
+}
+
+func loginIPKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// CheckLogin refuses sign-ins from addresses with too many failures and makes an account
+// wait longer after each failure beyond the first few
+func (s *lockoutService) CheckLogin(ctx context.Context, email string, device domain.DeviceInfo) error {
+	limits := s.limits
+
+	if device.IPAddress != "" && limits.MaxIPFailures > 0 {
+		failures, _, err := s.attempts.Recent(ctx, loginIPKey(device.IPAddress), limits.Window)
+		if err != nil {
+			return err
+		}
+		if failures >= limits.MaxIPFailures {
+			return domain.ErrTooManyFailedSignIns
+		}
+	}
+
+	failures, last, err := s.attempts.Recent(ctx, loginAccountKey(email), limits.Window)
+	if err != nil {
+		return err
+	}
+	if limits.DelayAfter <= 0 || failures < limits.DelayAfter {
+		return nil
+	}
+
+	delay := limits.BaseDelay
+	for i := limits.DelayAfter; i < failures && delay < limits.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > limits.MaxDelay {
+		delay = limits.MaxDelay
+	}
+
+	if wait := delay - time.Since(last); wait > 0 {
+		return domain.NewRateLimitedError(domain.ErrCodeTooManyAttempts, fmt.Sprintf("too many failed sign-ins, try again in %d seconds", int(wait.Seconds())+1))
+	}
+	return nil
+}
+
+// RecordFailure counts a failed sign-in, keeps it in the audit trail and locks the account
+// once it failed too often. Failures here must not hide the invalid credentials.
+func (s *lockoutService) RecordFailure(ctx context.Context, user *domain.User, email string, device domain.DeviceInfo, reason string) {
+	limits := s.limits
+
+	_ = s.attempts.Record(ctx, loginAccountKey(email), limits.Window)
+	if device.IPAddress != "" {
+		_ = s.attempts.Record(ctx, loginIPKey(device.IPAddress), limits.Window)
+	}
+
+	attempt := &domain.LoginAttempt{
+		Email:     email,
+		IPAddress: device.IPAddress,
+		UserAgent: device.UserAgent,
+		Reason:    reason,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	_ = s.attemptRepo.Create(ctx, nil, attempt)
+
+	if user == nil {
+		return
+	}
+
+	// Nobody is signed in yet, the entry is about the account rather than by it
+	entry := newAuditEntry(ctx, domain.AuditActionLoginFailed, domain.AuditTargetUser, user.ID.String())
+	entry.Detail = reason
+	_ = s.auditRepo.Create(ctx, nil, entry)
+
+	if limits.MaxAccountFailures <= 0 {
+		return
+	}
+
+	failures, _, err := s.attempts.Recent(ctx, loginAccountKey(email), limits.Window)
+	if err != nil || failures < limits.MaxAccountFailures {
+		return
+	}
+
+	lockedUntil := time.Now().Add(limits.LockoutDuration)
+	user.LockedUntil = &lockedUntil
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+		entry := newAuditEntry(ctx, domain.AuditActionAccountLocked, domain.AuditTargetUser, user.ID.String())
+		entry.Changes = auditChange("locked_until", nil, lockedUntil)
+		return s.auditRepo.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return
+	}
+
+	_ = s.sendUnlockEmail(ctx, user)
+}
+
+// ResetFailures forgets the failed sign-ins of the account once the user proved who they are
+func (s *lockoutService) ResetFailures(ctx context.Context, email string) error {
+	return s.attempts.Reset(ctx, loginAccountKey(email))
+}
+
+func (s *lockoutService) sendUnlockEmail(ctx context.Context, user *domain.User) error {
+	rawToken, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokenStore.Store(ctx, accountUnlockPurpose, token.Hash(rawToken), user.ID, s.limits.LockoutDuration); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/unlock-account?token=%s", s.appURL, rawToken)
+	body := fmt.Sprintf(
+		"Hello %s,\n\nYour account was locked after too many failed sign-ins. If that was you, unlock it here: %s\n\nOtherwise someone may be guessing your password; the account unlocks by itself at %s, consider choosing a stronger password.",
+		user.Username, link, user.LockedUntil.UTC().Format("15:04 MST on 2 January 2006"),
+	)
+	return s.mailer.Send(ctx, user.Email, "Your account was locked", body)
+}
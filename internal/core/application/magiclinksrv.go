@@ -0,0 +1,147 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/token"
+)
+
+const (
+	magicLinkPurpose = "magic_link"
+	magicLinkTTL     = 15 * time.Minute
+	// Sign-in links an address may ask for within magicLinkTTL, across all accounts
+	magicLinkMaxPerIP = 10
+)
+
+type magicLinkService struct {
+	txManager  ports.TransactionManager
+	userRepo   ports.UserRepository
+	tokenStore ports.OneTimeTokenStore
+	throttle   ports.Throttle
+	attempts   ports.AttemptCounter
+	sessions   ports.SessionIssuer
+	mailer     ports.Mailer
+	appURL     string
+}
+
+func NewMagicLinkService(
+	txManager ports.TransactionManager,
+	userRepo ports.UserRepository,
+	tokenStore ports.OneTimeTokenStore,
+	throttle ports.Throttle,
+	attempts ports.AttemptCounter,
+	sessions ports.SessionIssuer,
+	mailer ports.Mailer,
+	appURL string,
+) ports.MagicLinkService {
+	return &magicLinkService{
+		txManager:  txManager,
+		userRepo:   userRepo,
+		tokenStore: tokenStore,
+		throttle:   throttle,
+		attempts:   attempts,
+		sessions:   sessions,
+		mailer:     mailer,
+		appURL:     strings.TrimRight(appURL, "/"),
+	}
+}
+
+// RequestMagicLink emails a volunteer a single-use sign-in link. The link only works together
+// with the returned device binding, which stays with the device that asked for it, so a
+// forwarded or intercepted email is useless on its own. The binding is returned whether or
+// not a volunteer account exists for the address, along with the time the link expires.
+func (s *magicLinkService) RequestMagicLink(ctx context.Context, email string, device domain.DeviceInfo) (string, time.Time, error) {
+	if device.IPAddress != "" {
+		ipKey := "magic_link:ip:" + device.IPAddress
+		requests, _, err := s.attempts.Recent(ctx, ipKey, magicLinkTTL)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if requests >= magicLinkMaxPerIP {
+			return "", time.Time{}, domain.ErrTooManyMagicLinks
+		}
+		_ = s.attempts.Record(ctx, ipKey, magicLinkTTL)
+	}
+
+	binding, err := token.Generate()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(magicLinkTTL)
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || user.Type != domain.UserTypeVolunteer || user.IsSuspended() {
+		return binding, expiresAt, nil
+	}
+
+	// Quietly send at most one link a minute, an error would tell whether the account exists
+	if allowed, err := s.throttle.Allow(ctx, "magic_link:"+user.ID.String(), verificationResendInterval); err != nil || !allowed {
+		return binding, expiresAt, err
+	}
+
+	rawToken, err := token.Generate()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err := s.tokenStore.Store(ctx, magicLinkPurpose, magicLinkHash(rawToken, binding), user.ID, magicLinkTTL); err != nil {
+		return "", time.Time{}, err
+	}
+
+	link := fmt.Sprintf("%s/magic-link?token=%s", s.appURL, rawToken)
+	body := fmt.Sprintf(
+		"Hello %s,\n\nSign in here: %s\n\nThe link expires in 15 minutes and only works once, in the browser or app you requested it from. If you did not ask to sign in you can ignore this email.",
+		user.Username, link,
+	)
+	if err := s.mailer.Send(ctx, user.Email, "Your sign-in link", body); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return binding, expiresAt, nil
+}
+
+// ConsumeMagicLink signs a volunteer in with an emailed link, on the device it was requested
+// from. Opening the link proves the email address, two-factor authentication still applies.
+func (s *magicLinkService) ConsumeMagicLink(ctx context.Context, rawToken string, binding string, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, *domain.TwoFactorChallenge, error) {
+	userID, err := s.tokenStore.Consume(ctx, magicLinkPurpose, magicLinkHash(rawToken, binding))
+	if err != nil {
+		return nil, domain.TokenPair{}, nil, domain.ErrInvalidMagicLink
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user.Type != domain.UserTypeVolunteer {
+		return nil, domain.TokenPair{}, nil, domain.ErrInvalidMagicLink
+	}
+
+	if user.IsLocked() {
+		return nil, domain.TokenPair{}, nil, domain.ErrAccountLocked
+	}
+
+	if user.IsSuspended() {
+		return nil, domain.TokenPair{}, nil, domain.ErrAccountSuspended
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+			return s.userRepo.Update(ctx, tx, user)
+		})
+		if err != nil {
+			return nil, domain.TokenPair{}, nil, err
+		}
+	}
+
+	return s.sessions.CompleteSignIn(ctx, user, device)
+}
+
+// magicLinkHash ties a sign-in link to its device binding, only both together match the
+// stored hash
+func magicLinkHash(rawToken, binding string) string {
+	return token.Hash(rawToken + "." + binding)
+}
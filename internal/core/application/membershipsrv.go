@@ -0,0 +1,369 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/token"
+	"github.com/google/uuid"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+type membershipService struct {
+	txManager      ports.TransactionManager
+	userRepo       ports.UserRepository
+	restaurantRepo ports.RestaurantRepository
+	branchRepo     ports.BranchRepository
+	memberRepo     ports.RestaurantMemberRepository
+	invitationRepo ports.RestaurantInvitationRepository
+	tokenCache     ports.TokenCache
+	mailer         ports.Mailer
+	appURL         string
+}
+
+func NewMembershipService(
+	txManager ports.TransactionManager,
+	userRepo ports.UserRepository,
+	restaurantRepo ports.RestaurantRepository,
+	branchRepo ports.BranchRepository,
+	memberRepo ports.RestaurantMemberRepository,
+	invitationRepo ports.RestaurantInvitationRepository,
+	tokenCache ports.TokenCache,
+	mailer ports.Mailer,
+	appURL string,
+) ports.MembershipService {
+	return &membershipService{
+		txManager:      txManager,
+		userRepo:       userRepo,
+		restaurantRepo: restaurantRepo,
+		branchRepo:     branchRepo,
+		memberRepo:     memberRepo,
+		invitationRepo: invitationRepo,
+		tokenCache:     tokenCache,
+		mailer:         mailer,
+		appURL:         strings.TrimRight(appURL, "/"),
+	}
+}
+
+// ResolveMembership returns the membership the user acts through. The restaurant ID may be
+// empty for users that belong to a single restaurant.
+func (s *membershipService) ResolveMembership(ctx context.Context, userID string, restaurantID string) (*domain.RestaurantMember, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	if restaurantID != "" {
+		rid, err := uuid.Parse(restaurantID)
+		if err != nil {
+			return nil, invalidID("restaurant", err)
+		}
+
+		member, err := s.memberRepo.GetByRestaurantAndUser(ctx, rid, uid)
+		if err != nil || member.Restaurant == nil {
+			return nil, domain.ErrNotAMember
+		}
+		return member, nil
+	}
+
+	members, err := s.memberRepo.GetByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(members) {
+	case 0:
+		return nil, domain.ErrNoMembership
+	case 1:
+		return members[0], nil
+	default:
+		return nil, domain.ErrRestaurantRequired
+	}
+}
+
+func (s *membershipService) GetMemberships(ctx context.Context, userID string) ([]*domain.RestaurantMember, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	return s.memberRepo.GetByUserID(ctx, uid)
+}
+
+func (s *membershipService) GetMembers(ctx context.Context, restaurantID string) ([]*domain.RestaurantMember, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	return s.memberRepo.GetByRestaurantID(ctx, rid)
+}
+
+func (s *membershipService) GetMember(ctx context.Context, id string) (*domain.RestaurantMember, error) {
+	mid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("member", err)
+	}
+
+	return s.memberRepo.GetByID(ctx, mid)
+}
+
+func (s *membershipService) UpdateMember(ctx context.Context, id string, update domain.UpdateMemberRequest) error {
+	mid, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("member", err)
+	}
+
+	member, err := s.memberRepo.GetByID(ctx, mid)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == domain.MembershipRoleOwner {
+		return domain.ErrOwnerRoleImmutable
+	}
+
+	if err := s.checkBranch(ctx, member.RestaurantID, update.BranchID); err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.memberRepo.UpdateAccess(ctx, tx, mid, update.Role, update.BranchID)
+	})
+}
+
+func (s *membershipService) RemoveMember(ctx context.Context, id string) error {
+	mid, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("member", err)
+	}
+
+	member, err := s.memberRepo.GetByID(ctx, mid)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == domain.MembershipRoleOwner {
+		return domain.ErrOwnerNotRemovable
+	}
+
+	memberships, err := s.memberRepo.GetByUserID(ctx, member.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Without any restaurant left the account falls back to a regular one
+	demote := len(memberships) == 1 && member.User != nil
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.memberRepo.Delete(ctx, tx, mid); err != nil {
+			return err
+		}
+
+		if !demote {
+			return nil
+		}
+
+		member.User.Type = domain.UserTypeRegular
+		return s.userRepo.Update(ctx, tx, member.User)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Existing sessions carry the old role
+	if demote {
+		_ = s.tokenCache.InvalidateUserSessions(ctx, member.UserID)
+	}
+
+	return nil
+}
+
+func (s *membershipService) InviteMember(ctx context.Context, restaurantID string, invitedBy string, req domain.InviteMemberRequest) (*domain.RestaurantInvitation, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	inviterID, err := uuid.Parse(invitedBy)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	restaurant, err := s.restaurantRepo.GetByID(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkBranch(ctx, rid, req.BranchID); err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	if existingUser, _ := s.userRepo.GetByEmail(ctx, email); existingUser != nil {
+		if member, _ := s.memberRepo.GetByRestaurantAndUser(ctx, rid, existingUser.ID); member != nil {
+			return nil, domain.ErrAlreadyMember
+		}
+	}
+
+	rawToken, err := token.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &domain.RestaurantInvitation{
+		RestaurantID: rid,
+		Email:        email,
+		Role:         req.Role,
+		BranchID:     req.BranchID,
+		TokenHash:    token.Hash(rawToken),
+		InvitedBy:    inviterID,
+		ExpiresAt:    time.Now().Add(invitationTTL),
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		// A new invitation replaces any earlier one sent to the same address
+		if err := s.invitationRepo.DeletePending(ctx, tx, rid, email); err != nil {
+			return err
+		}
+
+		if err := s.invitationRepo.Create(ctx, tx, invitation); err != nil {
+			return err
+		}
+
+		// Sending last rolls the invitation back when the email cannot be delivered
+		link := fmt.Sprintf("%s/invitations/accept?token=%s", s.appURL, rawToken)
+		body := fmt.Sprintf(
+			"You have been invited to join %s as %s.\n\nAccept the invitation here: %s\n\nThe link expires on %s.",
+			restaurant.Name, invitation.Role, link, invitation.ExpiresAt.Format("2 January 2006"),
+		)
+		return s.mailer.Send(ctx, email, "Invitation to join "+restaurant.Name, body)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (s *membershipService) GetInvitations(ctx context.Context, restaurantID string) ([]*domain.RestaurantInvitation, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	return s.invitationRepo.GetPendingByRestaurantID(ctx, rid)
+}
+
+func (s *membershipService) GetInvitation(ctx context.Context, id string) (*domain.RestaurantInvitation, error) {
+	iid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("invitation", err)
+	}
+
+	return s.invitationRepo.GetByID(ctx, iid)
+}
+
+func (s *membershipService) RevokeInvitation(ctx context.Context, id string) error {
+	iid, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("invitation", err)
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.invitationRepo.Delete(ctx, tx, iid)
+	})
+}
+
+func (s *membershipService) AcceptInvitation(ctx context.Context, userID string, rawToken string) (*domain.RestaurantMember, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := findPendingInvitation(ctx, s.invitationRepo, rawToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, domain.ErrInvitationEmailMismatch
+	}
+
+	if user.Type != domain.UserTypeRestaurant && user.Type != domain.UserTypeRegular {
+		return nil, domain.ErrInvitationAccountType
+	}
+
+	if member, _ := s.memberRepo.GetByRestaurantAndUser(ctx, invitation.RestaurantID, uid); member != nil {
+		return nil, domain.ErrAlreadyJoined
+	}
+
+	promote := user.Type == domain.UserTypeRegular
+	member := &domain.RestaurantMember{
+		RestaurantID: invitation.RestaurantID,
+		UserID:       uid,
+		Role:         invitation.Role,
+		BranchID:     invitation.BranchID,
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.memberRepo.Create(ctx, tx, member); err != nil {
+			return err
+		}
+
+		if err := s.invitationRepo.MarkAccepted(ctx, tx, invitation.ID); err != nil {
+			return err
+		}
+
+		if !promote {
+			return nil
+		}
+
+		user.Type = domain.UserTypeRestaurant
+		return s.userRepo.Update(ctx, tx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The session of a promoted account carries the old role, the user has to log in again
+	if promote {
+		_ = s.tokenCache.InvalidateUserSessions(ctx, uid)
+	}
+
+	member.Restaurant = invitation.Restaurant
+	return member, nil
+}
+
+// checkBranch makes sure a member is only limited to a branch of their own restaurant
+func (s *membershipService) checkBranch(ctx context.Context, restaurantID uuid.UUID, branchID *uuid.UUID) error {
+	if branchID == nil {
+		return nil
+	}
+
+	branch, err := s.branchRepo.GetByID(ctx, *branchID)
+	if err != nil || branch.RestaurantID != restaurantID {
+		return domain.ErrBranchNotInRestaurant
+	}
+	return nil
+}
+
+// findPendingInvitation looks up the invitation an emailed token belongs to
+func findPendingInvitation(ctx context.Context, invitationRepo ports.RestaurantInvitationRepository, rawToken string) (*domain.RestaurantInvitation, error) {
+	invitation, err := invitationRepo.GetByTokenHash(ctx, token.Hash(rawToken))
+	if err != nil || !invitation.IsPending() {
+		return nil, domain.ErrInvalidInvitation
+	}
+	return invitation, nil
+}
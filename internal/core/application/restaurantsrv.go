@@ -2,7 +2,7 @@ package application
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
@@ -10,45 +10,56 @@ import (
 )
 
 type restaurantService struct {
-	txManager      ports.TransactionManager
-	restaurantRepo ports.RestaurantRepository
-	eventRepo      ports.EventRepository
-	volunteerRepo  ports.VolunteerRepository
-	appRepo        ports.VolunteerApplicationRepository
-	eventVolRepo   ports.EventVolunteerRepository
+	txManager       ports.TransactionManager
+	restaurantRepo  ports.RestaurantRepository
+	branchRepo      ports.BranchRepository
+	eventRepo       ports.EventRepository
+	volunteerRepo   ports.VolunteerRepository
+	appRepo         ports.VolunteerApplicationRepository
+	eventVolRepo    ports.EventVolunteerRepository
+	inventoryRepo   ports.InventoryRepository
+	consumptionRepo ports.InventoryConsumptionRepository
 }
 
 func NewRestaurantService(
 	txManager ports.TransactionManager,
 	restaurantRepo ports.RestaurantRepository,
+	branchRepo ports.BranchRepository,
 	eventRepo ports.EventRepository,
 	volunteerRepo ports.VolunteerRepository,
 	appRepo ports.VolunteerApplicationRepository,
 	eventVolRepo ports.EventVolunteerRepository,
+	inventoryRepo ports.InventoryRepository,
+	consumptionRepo ports.InventoryConsumptionRepository,
 ) ports.RestaurantService {
 	return &restaurantService{
-		txManager:      txManager,
-		restaurantRepo: restaurantRepo,
-		eventRepo:      eventRepo,
-		volunteerRepo:  volunteerRepo,
-		appRepo:        appRepo,
-		eventVolRepo:   eventVolRepo,
+		txManager:       txManager,
+		restaurantRepo:  restaurantRepo,
+		branchRepo:      branchRepo,
+		eventRepo:       eventRepo,
+		volunteerRepo:   volunteerRepo,
+		appRepo:         appRepo,
+		eventVolRepo:    eventVolRepo,
+		inventoryRepo:   inventoryRepo,
+		consumptionRepo: consumptionRepo,
 	}
 }
 
 func (s *restaurantService) GetRestaurantByUserID(ctx context.Context, userID string) (*domain.Restaurant, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, invalidID("user", err)
 	}
 
 	return s.restaurantRepo.GetByUserID(ctx, uid)
 }
 
-func (s *restaurantService) GetRestaurantStats(ctx context.Context, restaurantID string) (map[string]interface{}, error) {
+// GetRestaurantStats aggregates the stats of all branches, or reports those of a single
+// branch when branchID is set
+func (s *restaurantService) GetRestaurantStats(ctx context.Context, restaurantID string, branchID string) (map[string]interface{}, error) {
 	rid, err := uuid.Parse(restaurantID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid restaurant ID: %w", err)
+		return nil, invalidID("restaurant", err)
 	}
 
 	restaurant, err := s.restaurantRepo.GetByID(ctx, rid)
@@ -56,8 +67,38 @@ func (s *restaurantService) GetRestaurantStats(ctx context.Context, restaurantID
 		return nil, err
 	}
 
+	branchStats, err := s.branchRepo.GetStats(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	totalEvents, mealsServed := restaurant.TotalEvents, restaurant.MealsServed
+
+	var bid *uuid.UUID
+	if branchID != "" {
+		parsed, err := uuid.Parse(branchID)
+		if err != nil {
+			return nil, invalidID("branch", err)
+		}
+		bid = &parsed
+
+		var current *domain.BranchStats
+		for _, bs := range branchStats {
+			if bs.BranchID == parsed {
+				current = bs
+			}
+		}
+
+		if current == nil {
+			return nil, domain.ErrBranchNotInRestaurant
+		}
+
+		totalEvents, mealsServed = current.TotalEvents, current.MealsServed
+		branchStats = []*domain.BranchStats{current}
+	}
+
 	// Get upcoming events count
-	upcomingEvents, _, err := s.eventRepo.GetByRestaurantID(ctx, rid, string(domain.EventStatusUpcoming), 100, 0)
+	upcomingEvents, _, err := s.eventRepo.GetByRestaurantID(ctx, rid, bid, string(domain.EventStatusUpcoming), 100, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -77,14 +118,53 @@ func (s *restaurantService) GetRestaurantStats(ctx context.Context, restaurantID
 	if err != nil {
 		return nil, err
 	}
+	pendingApps = filterApplicationsByBranch(pendingApps, bid)
+
+	// Get ingredient usage and cost per event
+	events, _, err := s.eventRepo.GetByRestaurantID(ctx, rid, bid, "", 100, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.ID)
+	}
+	consumptions, err := s.consumptionRepo.GetByEventIDs(ctx, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	consumptionsByEvent := make(map[uuid.UUID][]*domain.InventoryConsumption, len(events))
+	for _, c := range consumptions {
+		consumptionsByEvent[c.EventID] = append(consumptionsByEvent[c.EventID], c)
+	}
+
+	var inventoryCost float64
+	eventSummaries := make([]*domain.EventInventorySummary, 0, len(events))
+	for _, event := range events {
+		summary := summarizeEventInventory(event, consumptionsByEvent[event.ID])
+		inventoryCost += summary.TotalCost
+		eventSummaries = append(eventSummaries, summary)
+	}
+
+	// Get ingredients about to expire. The inventory is shared by all branches, so this
+	// counts the whole restaurant even when branchID is set.
+	expiringItems, err := s.inventoryRepo.GetExpiring(ctx, rid, time.Now().Add(defaultExpiryAlertWindow))
+	if err != nil {
+		return nil, err
+	}
 
 	return map[string]interface{}{
-		"total_events":       restaurant.TotalEvents,
-		"meals_served":       restaurant.MealsServed,
+		"total_events":       totalEvents,
+		"meals_served":       mealsServed,
 		"rating":             restaurant.Rating,
 		"upcoming_events":    len(upcomingEvents),
 		"volunteers_engaged": totalVolunteers,
 		"pending_apps":       len(pendingApps),
+		"inventory_cost":     inventoryCost,
+		"expiring_items":     len(expiringItems),
+		"event_summaries":    eventSummaries,
+		"branches":           branchStats,
 	}, nil
 }
 
@@ -94,6 +174,70 @@ func (s *restaurantService) UpdateRestaurant(ctx context.Context, restaurant *do
 	})
 }
 
+func (s *restaurantService) CreateBranch(ctx context.Context, branch *domain.Branch) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.branchRepo.Create(ctx, tx, branch)
+	})
+}
+
+func (s *restaurantService) GetBranches(ctx context.Context, restaurantID string) ([]*domain.Branch, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	return s.branchRepo.GetByRestaurantID(ctx, rid)
+}
+
+func (s *restaurantService) GetBranch(ctx context.Context, id string) (*domain.Branch, error) {
+	bid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("branch", err)
+	}
+
+	return s.branchRepo.GetByID(ctx, bid)
+}
+
+func (s *restaurantService) UpdateBranch(ctx context.Context, branch *domain.Branch) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.branchRepo.Update(ctx, tx, branch)
+	})
+}
+
+func (s *restaurantService) DeleteBranch(ctx context.Context, id string) error {
+	bid, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("branch", err)
+	}
+
+	branch, err := s.branchRepo.GetByID(ctx, bid)
+	if err != nil {
+		return err
+	}
+
+	branches, err := s.branchRepo.GetByRestaurantID(ctx, branch.RestaurantID)
+	if err != nil {
+		return err
+	}
+
+	if len(branches) == 1 {
+		return domain.ErrLastBranch
+	}
+
+	_, upcoming, err := s.eventRepo.GetByRestaurantID(ctx, branch.RestaurantID, &bid, string(domain.EventStatusUpcoming), 1, 0)
+	if err != nil {
+		return err
+	}
+
+	if upcoming > 0 {
+		return domain.ErrBranchHasUpcomingEvents
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.branchRepo.Delete(ctx, tx, bid)
+	})
+}
+
 func (s *restaurantService) CreateEvent(ctx context.Context, event *domain.Event) error {
 	// Set initial status
 	event.Status = domain.EventStatusUpcoming
@@ -123,7 +267,7 @@ func (s *restaurantService) UpdateEvent(ctx context.Context, event *domain.Event
 func (s *restaurantService) DeleteEvent(ctx context.Context, eventID string) error {
 	id, err := uuid.Parse(eventID)
 	if err != nil {
-		return fmt.Errorf("invalid event ID: %w", err)
+		return invalidID("event", err)
 	}
 
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
@@ -147,3 +291,19 @@ func (s *restaurantService) DeleteEvent(ctx context.Context, eventID string) err
 		return s.restaurantRepo.UpdateStats(ctx, tx, restaurant.ID, restaurant.TotalEvents-1, restaurant.MealsServed, restaurant.Rating)
 	})
 }
+
+// filterApplicationsByBranch keeps the applications for events of the branch, all of them
+// when branchID is nil
+func filterApplicationsByBranch(apps []*domain.VolunteerApplication, branchID *uuid.UUID) []*domain.VolunteerApplication {
+	if branchID == nil {
+		return apps
+	}
+
+	filtered := make([]*domain.VolunteerApplication, 0, len(apps))
+	for _, app := range apps {
+		if app.Event.BranchID != nil && *app.Event.BranchID == *branchID {
+			filtered = append(filtered, app)
+		}
+	}
+	return filtered
+}
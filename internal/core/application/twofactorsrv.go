@@ -0,0 +1,397 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/token"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/totp"
+)
+
+const (
+	twoFactorChallengePurpose = "two_factor_challenge"
+	twoFactorChallengeTTL     = 5 * time.Minute
+	// A TOTP code is accepted once, for a little longer than it stays valid
+	totpReplayWindow  = 2 * time.Minute
+	totpIssuer        = "DCF"
+	recoveryCodeCount = 10
+)
+
+type twoFactorService struct {
+	txManager    ports.TransactionManager
+	userRepo     ports.UserRepository
+	memberRepo   ports.RestaurantMemberRepository
+	recoveryRepo ports.RecoveryCodeRepository
+	policyRepo   ports.SecurityPolicyRepository
+	auditRepo    ports.AuditLogRepository
+	tokenStore   ports.OneTimeTokenStore
+	throttle     ports.Throttle
+	lockout      ports.LockoutService
+	mailer       ports.Mailer
+}
+
+func NewTwoFactorService(
+	txManager ports.TransactionManager,
+	userRepo ports.UserRepository,
+	memberRepo ports.RestaurantMemberRepository,
+	recoveryRepo ports.RecoveryCodeRepository,
+	policyRepo ports.SecurityPolicyRepository,
+	auditRepo ports.AuditLogRepository,
+	tokenStore ports.OneTimeTokenStore,
+	throttle ports.Throttle,
+	lockout ports.LockoutService,
+	mailer ports.Mailer,
+) ports.TwoFactorService {
+	return &twoFactorService{
+		txManager:    txManager,
+		userRepo:     userRepo,
+		memberRepo:   memberRepo,
+		recoveryRepo: recoveryRepo,
+		policyRepo:   policyRepo,
+		auditRepo:    auditRepo,
+		tokenStore:   tokenStore,
+		throttle:     throttle,
+		lockout:      lockout,
+		mailer:       mailer,
+	}
+}
+
+// VerifyChallenge returns the user who passed the password step once they entered a TOTP
+// code or a recovery code. Wrong codes count as failed sign-ins.
+func (s *twoFactorService) VerifyChallenge(ctx context.Context, req domain.TwoFactorLoginRequest, device domain.DeviceInfo) (*domain.User, error) {
+	challengeHash := token.Hash(req.ChallengeToken)
+	userID, err := s.tokenStore.Consume(ctx, twoFactorChallengePurpose, challengeHash)
+	if err != nil {
+		return nil, domain.ErrInvalidChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInvalidChallenge
+	}
+
+	if user.IsLocked() {
+		return nil, domain.ErrAccountLocked
+	}
+
+	if err := s.lockout.CheckLogin(ctx, user.Email, device); err != nil {
+		// Keep the challenge so the user can retry once the delay passed
+		_ = s.tokenStore.Store(ctx, twoFactorChallengePurpose, challengeHash, user.ID, twoFactorChallengeTTL)
+		return nil, err
+	}
+
+	valid, err := s.verifySecondFactor(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		s.lockout.RecordFailure(ctx, user, user.Email, device, "wrong_two_factor_code")
+		if !user.IsLocked() {
+			_ = s.tokenStore.Store(ctx, twoFactorChallengePurpose, challengeHash, user.ID, twoFactorChallengeTTL)
+		}
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	_ = s.lockout.ResetFailures(ctx, user.Email)
+
+	return user, nil
+}
+
+// CreateChallenge asks a user who passed the password step for their second factor, the
+// challenge is completed with VerifyChallenge
+func (s *twoFactorService) CreateChallenge(ctx context.Context, user *domain.User) (*domain.TwoFactorChallenge, error) {
+	rawToken, err := token.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenStore.Store(ctx, twoFactorChallengePurpose, token.Hash(rawToken), user.ID, twoFactorChallengeTTL); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    rawToken,
+		ExpiresAt:         time.Now().Add(twoFactorChallengeTTL),
+	}, nil
+}
+
+// verifySecondFactor accepts a current TOTP code or an unused recovery code, using it up
+func (s *twoFactorService) verifySecondFactor(ctx context.Context, user *domain.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if totp.Validate(code, user.TOTPSecret, time.Now()) {
+		// A code read over someone's shoulder cannot be used a second time
+		return s.throttle.Allow(ctx, fmt.Sprintf("totp:%s:%s", user.ID, code), totpReplayWindow)
+	}
+
+	if !user.IsTwoFactorEnabled() {
+		return false, nil
+	}
+	return s.recoveryRepo.Use(ctx, nil, user.ID, token.Hash(normalizeRecoveryCode(code)))
+}
+
+// SetupTwoFactor starts enrollment by generating a new TOTP secret. It is only used once
+// EnableTwoFactor confirmed the authenticator produces matching codes.
+func (s *twoFactorService) SetupTwoFactor(ctx context.Context, userID string, req domain.TwoFactorSetupRequest) (*domain.TwoFactorSetup, error) {
+	user, err := reauthenticate(ctx, s.userRepo, userID, req.CurrentPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.userRepo.Update(ctx, tx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorSetup{
+		Secret:     secret,
+		OTPAuthURL: totp.URI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// EnableTwoFactor turns two-factor authentication on once the code proves the authenticator
+// was set up, and returns the recovery codes. They are shown this one time only.
+func (s *twoFactorService) EnableTwoFactor(ctx context.Context, userID string, code string) ([]string, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, domain.ErrTwoFactorSetupRequired
+	}
+
+	valid, err := s.verifySecondFactor(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	codes, recoveryCodes, err := generateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TwoFactorEnabledAt = &now
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+		if err := s.recoveryRepo.ReplaceForUser(ctx, tx, user.ID, recoveryCodes); err != nil {
+			return err
+		}
+		return auditAccount(ctx, s.auditRepo, tx, domain.AuditActionTwoFactorEnabled, user.ID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf(
+		"Hello %s,\n\nTwo-factor authentication is now enabled on your account. Signing in will ask for a code from your authenticator app.\n\nIf you did not do this, reset your password right away.",
+		user.Username,
+	)
+	_ = s.mailer.Send(ctx, user.Email, "Two-factor authentication enabled", body)
+
+	return codes, nil
+}
+
+// DisableTwoFactor turns two-factor authentication off, unless the security policy
+// requires it of the user
+func (s *twoFactorService) DisableTwoFactor(ctx context.Context, userID string, req domain.DisableTwoFactorRequest) error {
+	user, err := reauthenticate(ctx, s.userRepo, userID, req.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsTwoFactorEnabled() {
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	required, err := s.twoFactorRequired(ctx, user)
+	if err != nil {
+		return err
+	}
+	if required {
+		return domain.ErrTwoFactorRequired
+	}
+
+	valid, err := s.verifySecondFactor(ctx, user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	user.TOTPSecret = ""
+	user.TwoFactorEnabledAt = nil
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+		if err := s.recoveryRepo.DeleteByUserID(ctx, tx, user.ID); err != nil {
+			return err
+		}
+		return auditAccount(ctx, s.auditRepo, tx, domain.AuditActionTwoFactorDisabled, user.ID, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hello %s,\n\nTwo-factor authentication was turned off on your account.\n\nIf you did not do this, reset your password right away.",
+		user.Username,
+	)
+	_ = s.mailer.Send(ctx, user.Email, "Two-factor authentication disabled", body)
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user, the old ones stop working
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID string, code string) ([]string, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if !user.IsTwoFactorEnabled() {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+
+	// Only an authenticator code will do, a recovery code must not be able to mint new ones
+	if !totp.Validate(strings.TrimSpace(code), user.TOTPSecret, time.Now()) {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	codes, recoveryCodes, err := generateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.recoveryRepo.ReplaceForUser(ctx, tx, user.ID, recoveryCodes); err != nil {
+			return err
+		}
+		return auditAccount(ctx, s.auditRepo, tx, domain.AuditActionRecoveryCodesRegenerated, user.ID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// TwoFactorEnrollmentRequired reports whether the user must enroll in two-factor
+// authentication before acting for their organization
+func (s *twoFactorService) TwoFactorEnrollmentRequired(ctx context.Context, userID string) (bool, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return false, invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, domain.ErrUserNotFound
+	}
+
+	return s.twoFactorEnrollmentRequired(ctx, user)
+}
+
+func (s *twoFactorService) twoFactorEnrollmentRequired(ctx context.Context, user *domain.User) (bool, error) {
+	if user.IsTwoFactorEnabled() {
+		return false, nil
+	}
+	return s.twoFactorRequired(ctx, user)
+}
+
+// twoFactorRequired reports whether the security policy requires two-factor authentication
+// of the user, which it does for organization owners when enabled
+func (s *twoFactorService) twoFactorRequired(ctx context.Context, user *domain.User) (bool, error) {
+	if user.Type != domain.UserTypeRestaurant {
+		return false, nil
+	}
+
+	policy, err := s.policyRepo.Get(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !policy.RequireOwnerTwoFactor {
+		return false, nil
+	}
+
+	members, err := s.memberRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, member := range members {
+		if member.Role == domain.MembershipRoleOwner {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// generateRecoveryCodes returns the codes to show the user along with the records holding
+// their hashes
+func generateRecoveryCodes(userID uuid.UUID) ([]string, []*domain.RecoveryCode, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]*domain.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := token.Generate()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Ten characters in two groups are easy to copy down by hand
+		code := normalizeRecoveryCode(raw)[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+		records = append(records, &domain.RecoveryCode{
+			UserID:   userID,
+			CodeHash: token.Hash(code),
+		})
+	}
+
+	return codes, records, nil
+}
+
+// normalizeRecoveryCode makes codes compare equal however they were typed
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "", "_", "").Replace(code)
+}
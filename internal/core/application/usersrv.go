@@ -2,18 +2,32 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	password_util "github.com/SOU9OUR-DCF/dcf-backend.git/pkg/password"
 	"github.com/google/uuid"
 )
 
 type userService struct {
-	txManager      ports.TransactionManager
-	userRepo       ports.UserRepository
-	restaurantRepo ports.RestaurantRepository
-	volunteerRepo  ports.VolunteerRepository
+	txManager           ports.TransactionManager
+	userRepo            ports.UserRepository
+	restaurantRepo      ports.RestaurantRepository
+	volunteerRepo       ports.VolunteerRepository
+	eventRepo           ports.EventRepository
+	appRepo             ports.VolunteerApplicationRepository
+	eventVolRepo        ports.EventVolunteerRepository
+	memberRepo          ports.RestaurantMemberRepository
+	identityRepo        ports.ExternalIdentityRepository
+	recoveryCodeRepo    ports.RecoveryCodeRepository
+	attemptRepo         ports.LoginAttemptRepository
+	auditRepo           ports.AuditLogRepository
+	tokenCache          ports.TokenCache
+	mailer              ports.Mailer
+	deletionGracePeriod time.Duration
 }
 
 func NewUserService(
@@ -21,19 +35,64 @@ func NewUserService(
 	userRepo ports.UserRepository,
 	restaurantRepo ports.RestaurantRepository,
 	volunteerRepo ports.VolunteerRepository,
+	eventRepo ports.EventRepository,
+	appRepo ports.VolunteerApplicationRepository,
+	eventVolRepo ports.EventVolunteerRepository,
+	memberRepo ports.RestaurantMemberRepository,
+	identityRepo ports.ExternalIdentityRepository,
+	recoveryCodeRepo ports.RecoveryCodeRepository,
+	attemptRepo ports.LoginAttemptRepository,
+	auditRepo ports.AuditLogRepository,
+	tokenCache ports.TokenCache,
+	mailer ports.Mailer,
+	deletionGracePeriod time.Duration,
 ) ports.UserService {
 	return &userService{
-		txManager:      txManager,
-		userRepo:       userRepo,
-		restaurantRepo: restaurantRepo,
-		volunteerRepo:  volunteerRepo,
+		txManager:           txManager,
+		userRepo:            userRepo,
+		restaurantRepo:      restaurantRepo,
+		volunteerRepo:       volunteerRepo,
+		eventRepo:           eventRepo,
+		appRepo:             appRepo,
+		eventVolRepo:        eventVolRepo,
+		memberRepo:          memberRepo,
+		identityRepo:        identityRepo,
+		recoveryCodeRepo:    recoveryCodeRepo,
+		attemptRepo:         attemptRepo,
+		auditRepo:           auditRepo,
+		tokenCache:          tokenCache,
+		mailer:              mailer,
+		deletionGracePeriod: deletionGracePeriod,
 	}
 }
 
+// profileOf loads the profile of the user's type, if any. Staff removed from their last
+// restaurant have no profile left but can still sign in to manage their account.
+func profileOf(ctx context.Context, restaurantRepo ports.RestaurantRepository, volunteerRepo ports.VolunteerRepository, user *domain.User) (interface{}, error) {
+	switch user.Type {
+	case domain.UserTypeRestaurant:
+		restaurant, err := restaurantRepo.GetByUserID(ctx, user.ID)
+		if errors.Is(err, domain.ErrNoMembership) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return restaurant, nil
+	case domain.UserTypeVolunteer:
+		volunteer, err := volunteerRepo.GetByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return volunteer, nil
+	}
+	return nil, nil
+}
+
 func (s *userService) GetUserByID(ctx context.Context, id string) (*domain.User, interface{}, error) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
-		return nil, nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, nil, invalidID("user", err)
 	}
 
 	user, err := s.userRepo.GetByID(ctx, uid)
@@ -42,14 +101,7 @@ func (s *userService) GetUserByID(ctx context.Context, id string) (*domain.User,
 	}
 
 	// Get the appropriate profile based on user type
-	var profile interface{}
-	switch user.Type {
-	case domain.UserTypeRestaurant:
-		profile, err = s.restaurantRepo.GetByUserID(ctx, user.ID)
-	case domain.UserTypeVolunteer:
-		profile, err = s.volunteerRepo.GetByUserID(ctx, user.ID)
-	}
-
+	profile, err := profileOf(ctx, s.restaurantRepo, s.volunteerRepo, user)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -66,7 +118,7 @@ func (s *userService) UpdateUser(ctx context.Context, user *domain.User) error {
 func (s *userService) GetUserProfile(ctx context.Context, userID string, userType domain.UserType) (interface{}, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, invalidID("user", err)
 	}
 
 	switch userType {
@@ -75,7 +127,7 @@ func (s *userService) GetUserProfile(ctx context.Context, userID string, userTyp
 	case domain.UserTypeVolunteer:
 		return s.volunteerRepo.GetByUserID(ctx, uid)
 	default:
-		return nil, fmt.Errorf("unsupported user type: %s", userType)
+		return nil, domain.NewValidationError("unsupported_user_type", fmt.Sprintf("unsupported user type: %s", userType))
 	}
 }
 
@@ -83,22 +135,270 @@ func (s *userService) UpdateUserProfile(ctx context.Context, userID string, prof
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
 		uid, err := uuid.Parse(userID)
 		if err != nil {
-			return fmt.Errorf("invalid user ID: %w", err)
+			return invalidID("user", err)
 		}
 
 		switch p := profile.(type) {
 		case *domain.Restaurant:
 			if p.UserID != uid {
-				return fmt.Errorf("profile does not belong to user")
+				return domain.ErrProfileNotOwned
 			}
 			return s.restaurantRepo.Update(ctx, tx, p)
 		case *domain.Volunteer:
 			if p.UserID != uid {
-				return fmt.Errorf("profile does not belong to user")
+				return domain.ErrProfileNotOwned
 			}
 			return s.volunteerRepo.Update(ctx, tx, p)
 		default:
-			return fmt.Errorf("unsupported profile type")
+			return domain.ErrUnsupportedProfileType
+		}
+	})
+}
+
+// ExportUserData collects everything stored about the user so they can download a copy
+func (s *userService) ExportUserData(ctx context.Context, userID string) (*domain.UserDataExport, error) {
+	user, profile, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.UserDataExport{
+		ExportedAt:   time.Now(),
+		User:         user,
+		Profile:      profile,
+		Applications: []*domain.VolunteerApplication{},
+		Events:       []*domain.ExportedEvent{},
+	}
+
+	export.Memberships, err = s.memberRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.Identities, err = s.identityRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	volunteer, ok := profile.(*domain.Volunteer)
+	if !ok {
+		return export, nil
+	}
+
+	export.Applications, err = s.appRepo.GetByVolunteerID(ctx, volunteer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.eventVolRepo.GetByVolunteerID(ctx, volunteer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assignment := range assignments {
+		exported := &domain.ExportedEvent{
+			EventID:    assignment.EventID,
+			Role:       assignment.Role,
+			CheckedIn:  assignment.CheckedIn,
+			AssignedAt: assignment.CreatedAt,
+		}
+		// The event may have been deleted since, the assignment is still the user's data
+		if event, err := s.eventRepo.GetByID(ctx, assignment.EventID); err == nil {
+			exported.Title = event.Title
+			exported.Date = event.Date
+			exported.Location = event.Location
+			exported.Status = event.Status
+		}
+		export.Events = append(export.Events, exported)
+	}
+
+	return export, nil
+}
+
+// RequestAccountDeletion schedules the erasure of the account after the grace period and
+// signs out the user's other sessions. The user can still sign in and cancel until then.
+func (s *userService) RequestAccountDeletion(ctx context.Context, userID string, sessionID string, req domain.DeleteAccountRequest) (*domain.User, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !password_util.Verify(req.CurrentPassword, user.Password) {
+		return nil, domain.ErrIncorrectPassword
+	}
+
+	if user.IsDeletionScheduled() {
+		return nil, domain.ErrDeletionAlreadyScheduled
+	}
+
+	if user.Type == domain.UserTypeAdmin {
+		return nil, domain.ErrAdminNotDeletable
+	}
+
+	// Deleting the owner would leave the organization without anyone to run it
+	memberships, err := s.memberRepo.GetByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, membership := range memberships {
+		if membership.Role == domain.MembershipRoleOwner {
+			return nil, domain.ErrOwnerNotDeletable
 		}
+	}
+
+	dueAt := time.Now().Add(s.deletionGracePeriod)
+	user.DeletionDueAt = &dueAt
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionAccountDeletionRequested, domain.AuditTargetUser, user.ID.String())
+		entry.Changes = auditChange("deletion_due_at", nil, dueAt)
+		return s.auditRepo.Create(ctx, tx, entry)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.tokenCache.ListSessions(ctx, uid)
+	if err == nil {
+		for _, session := range sessions {
+			if session.ID.String() != sessionID {
+				_ = s.tokenCache.InvalidateSession(ctx, uid, session.ID)
+			}
+		}
+	}
+
+	body := fmt.Sprintf(
+		"Hello %s,\n\nYour account and personal data will be deleted on %s. Until then you can sign in and cancel the deletion from your account settings.\n\nIf you did not ask for this, sign in, cancel the deletion and reset your password right away.",
+		user.Username, dueAt.UTC().Format("2 January 2006"),
+	)
+	_ = s.mailer.Send(ctx, user.Email, "Your account will be deleted", body)
+
+	return user, nil
+}
+
+func (s *userService) CancelAccountDeletion(ctx context.Context, userID string) (*domain.User, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, invalidID("user", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsDeletionScheduled() {
+		return nil, domain.ErrDeletionNotScheduled
+	}
+
+	dueAt := user.DeletionDueAt
+	user.DeletionDueAt = nil
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.userRepo.Update(ctx, tx, user); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionAccountDeletionCancelled, domain.AuditTargetUser, user.ID.String())
+		entry.Changes = auditChange("deletion_due_at", dueAt, nil)
+		return s.auditRepo.Create(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// PurgeDueAccounts erases the accounts whose grace period is over and returns how many
+// were erased. It keeps going past accounts that fail and returns the last error.
+func (s *userService) PurgeDueAccounts(ctx context.Context) (int, error) {
+	users, err := s.userRepo.GetDueForDeletion(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	var lastErr error
+	for _, user := range users {
+		if err := s.purgeAccount(ctx, user); err != nil {
+			lastErr = fmt.Errorf("failed to delete user %s: %w", user.ID, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, lastErr
+}
+
+// purgeAccount removes the user and what identifies them. A volunteer profile is kept
+// under a placeholder name so applications and assignments, and with them the stats of
+// the restaurants they helped, stay intact.
+func (s *userService) purgeAccount(ctx context.Context, user *domain.User) error {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if volunteer, err := s.volunteerRepo.GetByUserID(ctx, user.ID); err == nil {
+			applications, err := s.appRepo.GetByVolunteerID(ctx, volunteer.ID)
+			if err != nil {
+				return err
+			}
+			for _, application := range applications {
+				if application.Status != "pending" {
+					continue
+				}
+				if err := s.appRepo.Delete(ctx, tx, application.ID); err != nil {
+					return err
+				}
+			}
+
+			volunteer.UserID = uuid.Nil
+			volunteer.FullName = domain.DeletedVolunteerName
+			volunteer.PhoneNumber = ""
+			volunteer.Address = ""
+			if err := s.volunteerRepo.Update(ctx, tx, volunteer); err != nil {
+				return err
+			}
+		}
+
+		if err := s.memberRepo.DeleteByUserID(ctx, tx, user.ID); err != nil {
+			return err
+		}
+		if err := s.identityRepo.DeleteByUserID(ctx, tx, user.ID); err != nil {
+			return err
+		}
+		if err := s.recoveryCodeRepo.DeleteByUserID(ctx, tx, user.ID); err != nil {
+			return err
+		}
+		if err := s.attemptRepo.DeleteByUserID(ctx, tx, user.ID); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.Purge(ctx, tx, user.ID); err != nil {
+			return err
+		}
+
+		// The entry keeps the account's ID only, nothing that identifies the person
+		return s.auditRepo.Create(ctx, tx, newAuditEntry(ctx, domain.AuditActionAccountDeleted, domain.AuditTargetUser, user.ID.String()))
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = s.tokenCache.InvalidateUserSessions(ctx, user.ID)
+
+	body := fmt.Sprintf(
+		"Hello %s,\n\nAs you asked, your account and personal data have been deleted. Thank you for being part of the community.",
+		user.Username,
+	)
+	_ = s.mailer.Send(ctx, user.Email, "Your account was deleted", body)
+
+	return nil
 }
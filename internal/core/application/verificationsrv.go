@@ -0,0 +1,222 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// Content types accepted for verification documents, keyed by the sniffed type
+var documentExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+type verificationService struct {
+	txManager      ports.TransactionManager
+	restaurantRepo ports.RestaurantRepository
+	documentRepo   ports.RestaurantDocumentRepository
+	storage        ports.FileStorage
+}
+
+func NewVerificationService(
+	txManager ports.TransactionManager,
+	restaurantRepo ports.RestaurantRepository,
+	documentRepo ports.RestaurantDocumentRepository,
+	storage ports.FileStorage,
+) ports.VerificationService {
+	return &verificationService{
+		txManager:      txManager,
+		restaurantRepo: restaurantRepo,
+		documentRepo:   documentRepo,
+		storage:        storage,
+	}
+}
+
+func (s *verificationService) UploadDocument(ctx context.Context, restaurantID string, documentType domain.DocumentType, fileName string, content io.Reader) (*domain.RestaurantDocument, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	restaurant, err := s.restaurantRepo.GetByID(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	if restaurant.IsVerified() {
+		return nil, domain.ErrRestaurantVerified
+	}
+
+	// Trust the file content rather than the client supplied content type
+	reader := bufio.NewReaderSize(content, 512)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(head)
+	if i := strings.Index(contentType, ";"); i != -1 {
+		contentType = contentType[:i]
+	}
+
+	ext, ok := documentExtensions[contentType]
+	if !ok {
+		return nil, domain.NewValidationError("unsupported_document_type", fmt.Sprintf("unsupported document type: %s", contentType))
+	}
+
+	document := &domain.RestaurantDocument{
+		ID:           uuid.New(),
+		RestaurantID: rid,
+		DocumentType: documentType,
+		FileName:     filepath.Base(fileName),
+		ContentType:  contentType,
+	}
+	document.StorageKey = fmt.Sprintf("restaurants/%s/documents/%s%s", rid, document.ID, ext)
+
+	counter := &countingReader{r: reader}
+	if err := s.storage.Save(ctx, document.StorageKey, counter); err != nil {
+		return nil, fmt.Errorf("failed to store document: %w", err)
+	}
+	document.Size = counter.n
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		if err := s.documentRepo.Create(ctx, tx, document); err != nil {
+			return err
+		}
+
+		// New documents put a rejected restaurant back in the review queue
+		if restaurant.VerificationStatus == domain.VerificationStatusRejected {
+			return s.restaurantRepo.UpdateVerification(ctx, tx, rid, domain.VerificationStatusPending, "")
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = s.storage.Delete(ctx, document.StorageKey)
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (s *verificationService) GetDocuments(ctx context.Context, restaurantID string) ([]*domain.RestaurantDocument, error) {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return nil, invalidID("restaurant", err)
+	}
+
+	return s.documentRepo.GetByRestaurantID(ctx, rid)
+}
+
+func (s *verificationService) GetDocument(ctx context.Context, id string) (*domain.RestaurantDocument, error) {
+	did, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("document", err)
+	}
+
+	return s.documentRepo.GetByID(ctx, did)
+}
+
+func (s *verificationService) OpenDocument(ctx context.Context, document *domain.RestaurantDocument) (io.ReadCloser, error) {
+	return s.storage.Open(ctx, document.StorageKey)
+}
+
+func (s *verificationService) DeleteDocument(ctx context.Context, id string) error {
+	did, err := uuid.Parse(id)
+	if err != nil {
+		return invalidID("document", err)
+	}
+
+	document, err := s.documentRepo.GetByID(ctx, did)
+	if err != nil {
+		return err
+	}
+
+	restaurant, err := s.restaurantRepo.GetByID(ctx, document.RestaurantID)
+	if err != nil {
+		return err
+	}
+
+	// Keep the evidence the approval was based on
+	if restaurant.IsVerified() {
+		return domain.ErrVerifiedDocumentsLocked
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.documentRepo.Delete(ctx, tx, did)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The record is gone, a leftover file is harmless
+	_ = s.storage.Delete(ctx, document.StorageKey)
+
+	return nil
+}
+
+func (s *verificationService) ApproveRestaurant(ctx context.Context, restaurantID string) error {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return invalidID("restaurant", err)
+	}
+
+	restaurant, err := s.restaurantRepo.GetByID(ctx, rid)
+	if err != nil {
+		return err
+	}
+
+	if restaurant.IsVerified() {
+		return nil
+	}
+
+	documents, err := s.documentRepo.GetByRestaurantID(ctx, rid)
+	if err != nil {
+		return err
+	}
+
+	if len(documents) == 0 {
+		return domain.ErrNoDocumentsSubmitted
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.restaurantRepo.UpdateVerification(ctx, tx, rid, domain.VerificationStatusVerified, "")
+	})
+}
+
+func (s *verificationService) RejectRestaurant(ctx context.Context, restaurantID string, reason string) error {
+	rid, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return invalidID("restaurant", err)
+	}
+
+	if _, err := s.restaurantRepo.GetByID(ctx, rid); err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
+		return s.restaurantRepo.UpdateVerification(ctx, tx, rid, domain.VerificationStatusRejected, reason)
+	})
+}
+
+// countingReader records how many bytes were read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
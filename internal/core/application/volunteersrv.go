@@ -2,7 +2,6 @@ package application
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/ports"
@@ -16,6 +15,7 @@ type volunteerService struct {
 	eventVolRepo   ports.EventVolunteerRepository
 	eventRepo      ports.EventRepository
 	restaurantRepo ports.RestaurantRepository
+	auditRepo      ports.AuditLogRepository
 }
 
 func NewVolunteerService(
@@ -25,6 +25,7 @@ func NewVolunteerService(
 	eventVolRepo ports.EventVolunteerRepository,
 	eventRepo ports.EventRepository,
 	restaurantRepo ports.RestaurantRepository,
+	auditRepo ports.AuditLogRepository,
 ) ports.VolunteerService {
 	return &volunteerService{
 		txManager:      txManager,
@@ -33,13 +34,14 @@ func NewVolunteerService(
 		eventVolRepo:   eventVolRepo,
 		eventRepo:      eventRepo,
 		restaurantRepo: restaurantRepo,
+		auditRepo:      auditRepo,
 	}
 }
 
 func (s *volunteerService) GetVolunteerByUserID(ctx context.Context, userID string) (*domain.Volunteer, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, invalidID("user", err)
 	}
 
 	return s.volunteerRepo.GetByUserID(ctx, uid)
@@ -48,7 +50,7 @@ func (s *volunteerService) GetVolunteerByUserID(ctx context.Context, userID stri
 func (s *volunteerService) GetEventVolunteers(ctx context.Context, eventID string) ([]*domain.Volunteer, error) {
 	eid, err := uuid.Parse(eventID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid event ID: %w", err)
+		return nil, invalidID("event", err)
 	}
 
 	// Get event volunteers
@@ -73,16 +75,25 @@ func (s *volunteerService) GetEventVolunteers(ctx context.Context, eventID strin
 func (s *volunteerService) GetPendingApplications(ctx context.Context, restaurantID string) ([]*domain.VolunteerApplication, error) {
 	rid, err := uuid.Parse(restaurantID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid restaurant ID: %w", err)
+		return nil, invalidID("restaurant", err)
 	}
 
 	return s.appRepo.GetByRestaurantID(ctx, rid, "pending")
 }
 
+func (s *volunteerService) GetApplication(ctx context.Context, id string) (*domain.VolunteerApplication, error) {
+	appID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, invalidID("application", err)
+	}
+
+	return s.appRepo.GetByID(ctx, appID)
+}
+
 func (s *volunteerService) ApproveApplication(ctx context.Context, applicationID string) error {
 	appID, err := uuid.Parse(applicationID)
 	if err != nil {
-		return fmt.Errorf("invalid application ID: %w", err)
+		return invalidID("application", err)
 	}
 
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
@@ -105,29 +116,46 @@ func (s *volunteerService) ApproveApplication(ctx context.Context, applicationID
 			CheckedIn:   false,
 		}
 
-		return s.eventVolRepo.Create(ctx, tx, eventVolunteer)
+		if err := s.eventVolRepo.Create(ctx, tx, eventVolunteer); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionApplicationApproved, domain.AuditTargetApplication, app.ID.String())
+		entry.Changes = auditChange("status", app.Status, "approved")
+		return s.auditRepo.Create(ctx, tx, entry)
 	})
 }
 
 func (s *volunteerService) DeclineApplication(ctx context.Context, applicationID string) error {
 	appID, err := uuid.Parse(applicationID)
 	if err != nil {
-		return fmt.Errorf("invalid application ID: %w", err)
+		return invalidID("application", err)
+	}
+
+	app, err := s.appRepo.GetByID(ctx, appID)
+	if err != nil {
+		return err
 	}
 
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
-		return s.appRepo.UpdateStatus(ctx, tx, appID, "declined")
+		if err := s.appRepo.UpdateStatus(ctx, tx, appID, "declined"); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditActionApplicationDeclined, domain.AuditTargetApplication, app.ID.String())
+		entry.Changes = auditChange("status", app.Status, "declined")
+		return s.auditRepo.Create(ctx, tx, entry)
 	})
 }
 
 func (s *volunteerService) GetVolunteerCount(ctx context.Context, restaurantID string) (int, error) {
 	rid, err := uuid.Parse(restaurantID)
 	if err != nil {
-		return 0, fmt.Errorf("invalid restaurant ID: %w", err)
+		return 0, invalidID("restaurant", err)
 	}
 
 	// Get all events for this restaurant
-	events, _, err := s.eventRepo.GetByRestaurantID(ctx, rid, "", 1000, 0)
+	events, _, err := s.eventRepo.GetByRestaurantID(ctx, rid, nil, "", 1000, 0)
 	if err != nil {
 		return 0, err
 	}
@@ -151,7 +179,7 @@ func (s *volunteerService) GetVolunteerCount(ctx context.Context, restaurantID s
 func (s *volunteerService) GetVolunteerDashboard(ctx context.Context, volunteerID string) (map[string]interface{}, error) {
 	vid, err := uuid.Parse(volunteerID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid volunteer ID: %w", err)
+		return nil, invalidID("volunteer", err)
 	}
 
 	// Get volunteer profile
@@ -227,7 +255,7 @@ func (s *volunteerService) GetVolunteerDashboard(ctx context.Context, volunteerI
 func (s *volunteerService) GetUpcomingTasks(ctx context.Context, volunteerID string) ([]map[string]interface{}, error) {
 	vid, err := uuid.Parse(volunteerID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid volunteer ID: %w", err)
+		return nil, invalidID("volunteer", err)
 	}
 
 	// Get all event volunteers for this volunteer
@@ -254,18 +282,19 @@ func (s *volunteerService) GetUpcomingTasks(ctx context.Context, volunteerID str
 			}
 
 			upcomingTasks = append(upcomingTasks, map[string]interface{}{
-				"id":         ev.ID,
-				"event_id":   event.ID,
-				"title":      event.Title,
-				"role":       ev.Role,
-				"location":   event.Location,
-				"restaurant": restaurant.Name,
-				"date":       event.Date,
-				"start_time": event.StartTime,
-				"end_time":   event.EndTime,
-				"status":     event.Status,
-				"checked_in": ev.CheckedIn,
-				"confirmed":  true, // Assuming if they're in event_volunteers, they're confirmed
+				"id":                ev.ID,
+				"event_id":          event.ID,
+				"title":             event.Title,
+				"role":              ev.Role,
+				"location":          event.Location,
+				"restaurant":        restaurant.Name,
+				"organization_type": restaurant.OrganizationType,
+				"date":              event.Date,
+				"start_time":        event.StartTime,
+				"end_time":          event.EndTime,
+				"status":            event.Status,
+				"checked_in":        ev.CheckedIn,
+				"confirmed":         true, // Assuming if they're in event_volunteers, they're confirmed
 			})
 		}
 	}
@@ -291,19 +320,20 @@ func (s *volunteerService) GetUpcomingTasks(ctx context.Context, volunteerID str
 			}
 
 			upcomingTasks = append(upcomingTasks, map[string]interface{}{
-				"id":         app.ID,
-				"event_id":   event.ID,
-				"title":      event.Title,
-				"role":       app.Role,
-				"location":   event.Location,
-				"restaurant": restaurant.Name,
-				"date":       event.Date,
-				"start_time": event.StartTime,
-				"end_time":   event.EndTime,
-				"status":     event.Status,
-				"checked_in": false,
-				"confirmed":  false,
-				"pending":    true,
+				"id":                app.ID,
+				"event_id":          event.ID,
+				"title":             event.Title,
+				"role":              app.Role,
+				"location":          event.Location,
+				"restaurant":        restaurant.Name,
+				"organization_type": restaurant.OrganizationType,
+				"date":              event.Date,
+				"start_time":        event.StartTime,
+				"end_time":          event.EndTime,
+				"status":            event.Status,
+				"checked_in":        false,
+				"confirmed":         false,
+				"pending":           true,
 			})
 		}
 	}
@@ -314,7 +344,7 @@ func (s *volunteerService) GetUpcomingTasks(ctx context.Context, volunteerID str
 func (s *volunteerService) GetNearbyOpportunities(ctx context.Context, volunteerID string) ([]map[string]interface{}, error) {
 	vid, err := uuid.Parse(volunteerID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid volunteer ID: %w", err)
+		return nil, invalidID("volunteer", err)
 	}
 
 	// In a real app, we would use geolocation to find nearby events
@@ -376,6 +406,7 @@ func (s *volunteerService) GetNearbyOpportunities(ctx context.Context, volunteer
 				"event_id":          event.ID,
 				"title":             event.Title,
 				"restaurant_name":   restaurant.Name,
+				"organization_type": restaurant.OrganizationType,
 				"location":          event.Location,
 				"date":              event.Date,
 				"start_time":        event.StartTime,
@@ -393,7 +424,7 @@ func (s *volunteerService) GetNearbyOpportunities(ctx context.Context, volunteer
 func (s *volunteerService) GetVolunteerBadges(ctx context.Context, volunteerID string) ([]map[string]interface{}, error) {
 	vid, err := uuid.Parse(volunteerID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid volunteer ID: %w", err)
+		return nil, invalidID("volunteer", err)
 	}
 
 	// Get completed tasks count
@@ -477,12 +508,12 @@ func (s *volunteerService) GetVolunteerBadges(ctx context.Context, volunteerID s
 func (s *volunteerService) ApplyForEvent(ctx context.Context, volunteerID string, eventID string, role string) error {
 	vid, err := uuid.Parse(volunteerID)
 	if err != nil {
-		return fmt.Errorf("invalid volunteer ID: %w", err)
+		return invalidID("volunteer", err)
 	}
 
 	eid, err := uuid.Parse(eventID)
 	if err != nil {
-		return fmt.Errorf("invalid event ID: %w", err)
+		return invalidID("event", err)
 	}
 
 	// Check if volunteer already applied or is assigned to this event
@@ -493,7 +524,7 @@ func (s *volunteerService) ApplyForEvent(ctx context.Context, volunteerID string
 
 	for _, app := range applications {
 		if app.EventID == eid {
-			return fmt.Errorf("you have already applied for this event")
+			return domain.ErrAlreadyApplied
 		}
 	}
 
@@ -504,7 +535,7 @@ func (s *volunteerService) ApplyForEvent(ctx context.Context, volunteerID string
 
 	for _, ev := range eventVolunteers {
 		if ev.EventID == eid {
-			return fmt.Errorf("you are already assigned to this event")
+			return domain.ErrAlreadyAssigned
 		}
 	}
 
@@ -516,7 +547,17 @@ func (s *volunteerService) ApplyForEvent(ctx context.Context, volunteerID string
 
 	// Check if event is upcoming
 	if event.Status != domain.EventStatusUpcoming {
-		return fmt.Errorf("this event is not accepting volunteers")
+		return domain.ErrEventNotAccepting
+	}
+
+	// Events of unverified restaurants are not public yet
+	restaurant, err := s.restaurantRepo.GetByID(ctx, event.RestaurantID)
+	if err != nil {
+		return err
+	}
+
+	if !restaurant.IsVerified() {
+		return domain.ErrEventNotAccepting
 	}
 
 	// Check if event has reached max volunteers
@@ -526,7 +567,7 @@ func (s *volunteerService) ApplyForEvent(ctx context.Context, volunteerID string
 	}
 
 	if volunteerCount >= event.MaxVolunteers {
-		return fmt.Errorf("this event has reached its volunteer capacity")
+		return domain.ErrEventFull
 	}
 
 	// Create application
@@ -546,12 +587,12 @@ func (s *volunteerService) ApplyForEvent(ctx context.Context, volunteerID string
 func (s *volunteerService) CheckInForEvent(ctx context.Context, volunteerID string, eventVolunteerID string) error {
 	vid, err := uuid.Parse(volunteerID)
 	if err != nil {
-		return fmt.Errorf("invalid volunteer ID: %w", err)
+		return invalidID("volunteer", err)
 	}
 
 	evid, err := uuid.Parse(eventVolunteerID)
 	if err != nil {
-		return fmt.Errorf("invalid event volunteer ID: %w", err)
+		return invalidID("event volunteer", err)
 	}
 
 	// Verify this event volunteer belongs to this volunteer
@@ -569,7 +610,7 @@ func (s *volunteerService) CheckInForEvent(ctx context.Context, volunteerID stri
 	}
 
 	if targetEV == nil {
-		return fmt.Errorf("event not found for this volunteer")
+		return domain.ErrEventNotFound
 	}
 
 	// Get event to check if it's active
@@ -579,7 +620,7 @@ func (s *volunteerService) CheckInForEvent(ctx context.Context, volunteerID stri
 	}
 
 	if event.Status != domain.EventStatusActive {
-		return fmt.Errorf("check-in is only available for active events")
+		return domain.ErrCheckInUnavailable
 	}
 
 	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx interface{}) error {
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeletedVolunteerName replaces the name of a volunteer whose account was erased. Their
+// profile is kept without personal data so the events they took part in keep their history.
+const DeletedVolunteerName = "Deleted volunteer"
+
+type DeleteAccountRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+// UserDataExport is everything stored about a user, returned when they ask for a copy of
+// their data. Ratings and reputation points are part of the profile.
+type UserDataExport struct {
+	ExportedAt   time.Time               `json:"exported_at"`
+	User         *User                   `json:"user"`
+	Profile      interface{}             `json:"profile,omitempty"`
+	Memberships  []*RestaurantMember     `json:"memberships"`
+	Identities   []*ExternalIdentity     `json:"identities"`
+	Applications []*VolunteerApplication `json:"applications"`
+	Events       []*ExportedEvent        `json:"events"`
+}
+
+// ExportedEvent is an event the volunteer was assigned to, with their part in it
+type ExportedEvent struct {
+	EventID    uuid.UUID   `json:"event_id"`
+	Title      string      `json:"title"`
+	Date       time.Time   `json:"date"`
+	Location   string      `json:"location"`
+	Status     EventStatus `json:"status"`
+	Role       string      `json:"role"`
+	CheckedIn  bool        `json:"checked_in"`
+	AssignedAt time.Time   `json:"assigned_at"`
+}
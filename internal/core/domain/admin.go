@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ListFilter narrows down the records returned by the admin listing endpoints.
+// Query is matched case-insensitively against the record's searchable text fields.
+type ListFilter struct {
+	Query  string `form:"q"`
+	Status string `form:"status"`
+	Type   string `form:"type"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// Normalize clamps the pagination parameters to sane values
+func (f *ListFilter) Normalize() {
+	if f.Limit <= 0 {
+		f.Limit = defaultListLimit
+	}
+	if f.Limit > maxListLimit {
+		f.Limit = maxListLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+}
+
+type AdminUserUpdate struct {
+	Username *string   `json:"username"`
+	Email    *string   `json:"email" binding:"omitempty,email"`
+	UserType *UserType `json:"user_type" binding:"omitempty,oneof=regular restaurant volunteer admin"`
+}
+
+// AdminRestaurantUpdate holds the descriptive fields of a restaurant administrators may
+// edit, stats are derived from events and verification has its own endpoints
+type AdminRestaurantUpdate struct {
+	Name          *string `json:"name" binding:"omitempty,min=1,max=255"`
+	Address       *string `json:"address" binding:"omitempty,max=255"`
+	ContactNumber *string `json:"contact_number" binding:"omitempty,max=50"`
+}
+
+// AdminEventUpdate holds the fields of an event administrators may edit, the status and
+// the meal count are changed through their own endpoints
+type AdminEventUpdate struct {
+	BranchID      *uuid.UUID `json:"branch_id"`
+	Title         *string    `json:"title" binding:"omitempty,min=1,max=255"`
+	Description   *string    `json:"description"`
+	Date          *time.Time `json:"date"`
+	StartTime     *time.Time `json:"start_time"`
+	EndTime       *time.Time `json:"end_time"`
+	Location      *string    `json:"location" binding:"omitempty,max=255"`
+	MaxGuests     *int       `json:"max_guests" binding:"omitempty,gte=0"`
+	MaxVolunteers *int       `json:"max_volunteers" binding:"omitempty,gte=0"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyScope limits what an API key may do on top of the permissions of the member who
+// created it
+type APIKeyScope string
+
+const (
+	APIKeyScopeEventsRead  APIKeyScope = "events:read"
+	APIKeyScopeEventsWrite APIKeyScope = "events:write"
+	APIKeyScopeMealsWrite  APIKeyScope = "meals:write"
+)
+
+// Every scope includes viewing events, the event routes require it. None of them grants
+// the restaurant's dashboard or its volunteer applications.
+var apiKeyScopePermissions = map[APIKeyScope][]Permission{
+	APIKeyScopeEventsRead:  {PermissionViewEvents},
+	APIKeyScopeEventsWrite: {PermissionViewEvents, PermissionManageEvents},
+	APIKeyScopeMealsWrite:  {PermissionViewEvents, PermissionRecordMeals},
+}
+
+// IsValid reports whether the scope is a known API key scope
+func (s APIKeyScope) IsValid() bool {
+	_, ok := apiKeyScopePermissions[s]
+	return ok
+}
+
+// APIKeyScopes is stored as a comma separated list
+type APIKeyScopes []APIKeyScope
+
+// Value implements driver.Valuer
+func (s APIKeyScopes) Value() (driver.Value, error) {
+	parts := make([]string, len(s))
+	for i, scope := range s {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Scan implements sql.Scanner
+func (s *APIKeyScopes) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	case nil:
+		*s = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into APIKeyScopes", value)
+	}
+
+	*s = nil
+	for _, part := range strings.Split(raw, ",") {
+		if part != "" {
+			*s = append(*s, APIKeyScope(part))
+		}
+	}
+	return nil
+}
+
+// APIKey lets a restaurant's own tools call the API. It acts with the permissions of the
+// member who created it, narrowed down to its scopes. Only the key's hash is stored.
+type APIKey struct {
+	ID           uuid.UUID    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	RestaurantID uuid.UUID    `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	Name         string       `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix       string       `gorm:"type:varchar(20);not null" json:"prefix"` // the start of the key, to tell keys apart
+	KeyHash      string       `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Scopes       APIKeyScopes `gorm:"type:varchar(255);not null" json:"scopes"`
+	CreatedBy    uuid.UUID    `gorm:"type:uuid;not null" json:"created_by"`
+	LastUsedAt   *time.Time   `json:"last_used_at"`
+	RevokedAt    *time.Time   `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time    `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsRevoked reports whether the key was revoked and no longer authenticates
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Allows reports whether one of the key's scopes grants the permission
+func (k *APIKey) Allows(permission Permission) bool {
+	for _, scope := range k.Scopes {
+		for _, p := range apiKeyScopePermissions[scope] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string        `json:"name" binding:"required,max=100"`
+	Scopes []APIKeyScope `json:"scopes" binding:"required,min=1,dive,oneof=events:read events:write meals:write"`
+}
+
+// CreatedAPIKey is returned once when a key is created, the key itself cannot be shown again
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,88 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyAllows(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     domain.APIKeyScopes
+		permission domain.Permission
+		want       bool
+	}{
+		{"events:read views events", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, domain.PermissionViewEvents, true},
+		{"events:read does not view the restaurant", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, domain.PermissionViewRestaurant, false},
+		{"events:read does not review applications", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, domain.PermissionReviewApplications, false},
+		{"events:read does not manage events", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, domain.PermissionManageEvents, false},
+		{"events:read does not record meals", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, domain.PermissionRecordMeals, false},
+		{"events:write manages events", domain.APIKeyScopes{domain.APIKeyScopeEventsWrite}, domain.PermissionManageEvents, true},
+		{"events:write does not record meals", domain.APIKeyScopes{domain.APIKeyScopeEventsWrite}, domain.PermissionRecordMeals, false},
+		{"meals:write records meals", domain.APIKeyScopes{domain.APIKeyScopeMealsWrite}, domain.PermissionRecordMeals, true},
+		{"meals:write does not manage events", domain.APIKeyScopes{domain.APIKeyScopeMealsWrite}, domain.PermissionManageEvents, false},
+		{"any scope of several", domain.APIKeyScopes{domain.APIKeyScopeEventsRead, domain.APIKeyScopeMealsWrite}, domain.PermissionRecordMeals, true},
+		{"no scope grants members", domain.APIKeyScopes{domain.APIKeyScopeEventsRead, domain.APIKeyScopeEventsWrite, domain.APIKeyScopeMealsWrite}, domain.PermissionManageMembers, false},
+		{"no scope grants inventory", domain.APIKeyScopes{domain.APIKeyScopeEventsRead, domain.APIKeyScopeEventsWrite, domain.APIKeyScopeMealsWrite}, domain.PermissionManageInventory, false},
+		{"no scope views the restaurant", domain.APIKeyScopes{domain.APIKeyScopeEventsRead, domain.APIKeyScopeEventsWrite, domain.APIKeyScopeMealsWrite}, domain.PermissionViewRestaurant, false},
+		{"unknown scope", domain.APIKeyScopes{"admin:all"}, domain.PermissionViewEvents, false},
+		{"no scopes", nil, domain.PermissionViewEvents, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &domain.APIKey{Scopes: tt.scopes}
+			assert.Equal(t, tt.want, key.Allows(tt.permission))
+		})
+	}
+}
+
+func TestAPIKeyScopesValue(t *testing.T) {
+	scopes := domain.APIKeyScopes{domain.APIKeyScopeEventsRead, domain.APIKeyScopeMealsWrite}
+
+	value, err := scopes.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "events:read,meals:write", value)
+
+	var scanned domain.APIKeyScopes
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, scopes, scanned)
+}
+
+func TestAPIKeyScopesScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    domain.APIKeyScopes
+		wantErr bool
+	}{
+		{"string", "events:read,events:write", domain.APIKeyScopes{domain.APIKeyScopeEventsRead, domain.APIKeyScopeEventsWrite}, false},
+		{"bytes", []byte("meals:write"), domain.APIKeyScopes{domain.APIKeyScopeMealsWrite}, false},
+		{"empty parts are skipped", ",events:read,", domain.APIKeyScopes{domain.APIKeyScopeEventsRead}, false},
+		{"nil", nil, nil, false},
+		{"unsupported type", 42, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scopes domain.APIKeyScopes
+			err := scopes.Scan(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, scopes)
+		})
+	}
+}
+
+func TestAPIKeyScopeIsValid(t *testing.T) {
+	assert.True(t, domain.APIKeyScopeEventsRead.IsValid())
+	assert.True(t, domain.APIKeyScopeEventsWrite.IsValid())
+	assert.True(t, domain.APIKeyScopeMealsWrite.IsValid())
+	assert.False(t, domain.APIKeyScope("events:delete").IsValid())
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditAction string
+
+const (
+	AuditActionLoginSucceeded           AuditAction = "auth.login_succeeded"
+	AuditActionLoginFailed              AuditAction = "auth.login_failed"
+	AuditActionAccountLocked            AuditAction = "auth.account_locked"
+	AuditActionAccountUnlocked          AuditAction = "auth.account_unlocked"
+	AuditActionLogout                   AuditAction = "auth.logout"
+	AuditActionPasswordChanged          AuditAction = "auth.password_changed"
+	AuditActionPasswordReset            AuditAction = "auth.password_reset"
+	AuditActionEmailChanged             AuditAction = "auth.email_changed"
+	AuditActionTwoFactorEnabled         AuditAction = "auth.two_factor_enabled"
+	AuditActionTwoFactorDisabled        AuditAction = "auth.two_factor_disabled"
+	AuditActionRecoveryCodesRegenerated AuditAction = "auth.recovery_codes_regenerated"
+
+	AuditActionImpersonationStarted AuditAction = "impersonation.started"
+	AuditActionImpersonationEnded   AuditAction = "impersonation.ended"
+	AuditActionImpersonatedRequest  AuditAction = "impersonation.request"
+
+	AuditActionUserUpdated           AuditAction = "user.updated"
+	AuditActionUserSuspended         AuditAction = "user.suspended"
+	AuditActionUserReactivated       AuditAction = "user.reactivated"
+	AuditActionSecurityPolicyUpdated AuditAction = "security_policy.updated"
+
+	AuditActionAccountDeletionRequested AuditAction = "account.deletion_requested"
+	AuditActionAccountDeletionCancelled AuditAction = "account.deletion_cancelled"
+	AuditActionAccountDeleted           AuditAction = "account.deleted"
+
+	AuditActionAPIKeyCreated AuditAction = "api_key.created"
+	AuditActionAPIKeyRevoked AuditAction = "api_key.revoked"
+
+	AuditActionApplicationApproved      AuditAction = "application.approved"
+	AuditActionApplicationDeclined      AuditAction = "application.declined"
+	AuditActionApplicationStatusChanged AuditAction = "application.status_changed"
+
+	AuditActionRestaurantUpdated AuditAction = "restaurant.updated"
+
+	AuditActionEventUpdated       AuditAction = "event.updated"
+	AuditActionEventStatusChanged AuditAction = "event.status_changed"
+	AuditActionMealsServedUpdated AuditAction = "event.meals_served_updated"
+	AuditActionMealsRecorded      AuditAction = "event.meals_recorded"
+	AuditActionMealEntryCorrected AuditAction = "event.meal_entry_corrected"
+)
+
+// Types of the records audit entries are about
+const (
+	AuditTargetUser           = "user"
+	AuditTargetSecurityPolicy = "security_policy"
+	AuditTargetAPIKey         = "api_key"
+	AuditTargetApplication    = "application"
+	AuditTargetRestaurant     = "restaurant"
+	AuditTargetEvent          = "event"
+)
+
+// AuditChange is the value of a field before and after an action
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditChanges maps the fields an action changed to their values before and after, it is
+// stored as JSON
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return errors.New("invalid audit changes")
+	}
+	return json.Unmarshal(data, c)
+}
+
+// AuditLog records who did what. ActorID is the account the action ran as; when an
+// administrator acted through an impersonated session, ImpersonatorID is that administrator.
+type AuditLog struct {
+	ID             uuid.UUID    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ActorID        *uuid.UUID   `gorm:"type:uuid;index" json:"actor_id,omitempty"`
+	ImpersonatorID *uuid.UUID   `gorm:"type:uuid;index" json:"impersonator_id,omitempty"`
+	APIKeyID       *uuid.UUID   `gorm:"type:uuid" json:"api_key_id,omitempty"` // when the actor acted through one of their API keys
+	Action         AuditAction  `gorm:"type:varchar(100);not null;index" json:"action"`
+	TargetType     string       `gorm:"type:varchar(50);index:idx_audit_target" json:"target_type,omitempty"`
+	TargetID       string       `gorm:"type:varchar(64);index:idx_audit_target" json:"target_id,omitempty"`
+	Detail         string       `gorm:"type:varchar(255)" json:"detail,omitempty"`
+	Changes        AuditChanges `gorm:"type:jsonb" json:"changes,omitempty"`
+	Method         string       `gorm:"type:varchar(10)" json:"method,omitempty"`
+	Path           string       `gorm:"type:varchar(255)" json:"path,omitempty"`
+	Status         int          `json:"status,omitempty"`
+	IPAddress      string       `gorm:"type:varchar(64)" json:"ip_address,omitempty"`
+	UserAgent      string       `gorm:"type:varchar(255)" json:"user_agent,omitempty"`
+	CreatedAt      time.Time    `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+type ImpersonationRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// AuditLogFilter narrows down the entries returned by the audit log endpoint
+type AuditLogFilter struct {
+	ActorID    string    `form:"actor_id" binding:"omitempty,uuid"`
+	Action     string    `form:"action"`
+	TargetType string    `form:"target_type"`
+	TargetID   string    `form:"target_id"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int       `form:"limit"`
+	Offset     int       `form:"offset"`
+}
+
+// Normalize clamps the pagination parameters to sane values
+func (f *AuditLogFilter) Normalize() {
+	if f.Limit <= 0 {
+		f.Limit = defaultListLimit
+	}
+	if f.Limit > maxListLimit {
+		f.Limit = maxListLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+}
+
+// AuditContext is who makes a request and how, the services attribute the audit entries
+// they write to it. The HTTP adapter stores it in the request's context.
+type AuditContext struct {
+	ActorID        *uuid.UUID
+	ImpersonatorID *uuid.UUID
+	APIKeyID       *uuid.UUID
+	Method         string
+	Path           string
+	IPAddress      string
+	UserAgent      string
+}
+
+type auditContextKey struct{}
+
+func WithAuditContext(ctx context.Context, auditCtx AuditContext) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, auditCtx)
+}
+
+// AuditContextFrom returns the audit context of ctx, empty for work not started by a request
+func AuditContextFrom(ctx context.Context) AuditContext {
+	auditCtx, _ := ctx.Value(auditContextKey{}).(AuditContext)
+	return auditCtx
+}
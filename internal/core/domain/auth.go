@@ -36,9 +36,20 @@ type VolunteerRegisterRequest struct {
 }
 
 type AuthResponse struct {
-	ExpiresAt time.Time   `json:"expires_at"`
-	User      User        `json:"user"`
-	Profile   interface{} `json:"profile,omitempty"` // Will contain the specific profile data
+	ExpiresAt        time.Time   `json:"expires_at"`         // of the access token
+	RefreshExpiresAt time.Time   `json:"refresh_expires_at"` // of the refresh token, and the session
+	User             User        `json:"user"`
+	Profile          interface{} `json:"profile,omitempty"`       // Will contain the specific profile data
+	AccessToken      string      `json:"access_token,omitempty"`  // only when the tokens are returned in the body
+	RefreshToken     string      `json:"refresh_token,omitempty"` // only when the tokens are returned in the body
+	CSRFToken        string      `json:"csrf_token,omitempty"`    // only when the tokens are set as cookies
+
+	// TwoFactorSetupRequired is set when the security policy requires the user to enroll in
+	// two-factor authentication before acting for their organization
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+
+	// ImpersonatorID is set on the sessions administrators open with /admin/users/:id/impersonate
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty"`
 }
 
 type LoginRequest struct {
@@ -46,6 +57,44 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// MagicLinkVerifyRequest carries the device binding in the body for clients that do not
+// keep cookies
+type MagicLinkVerifyRequest struct {
+	Token         string `json:"token" binding:"required"`
+	DeviceBinding string `json:"device_binding"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+type ChangeEmailRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewEmail        string `json:"new_email" binding:"required,email"`
+}
+
 type Token string
 
 func (t Token) String() string {
@@ -55,3 +104,10 @@ func (t Token) String() string {
 func NewToken(token string) Token {
 	return Token(token)
 }
+
+// TokenPair is a short-lived access token together with the opaque refresh token that
+// replaces it once it expires
+type TokenPair struct {
+	AccessToken  Token
+	RefreshToken Token
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Branch is a location of a restaurant where events take place. Every restaurant has
+// at least one branch, created from its address at registration.
+type Branch struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	RestaurantID  uuid.UUID      `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	Name          string         `gorm:"type:varchar(255);not null" json:"name" binding:"required"`
+	Address       string         `gorm:"type:varchar(255);not null" json:"address" binding:"required"`
+	ContactNumber string         `gorm:"type:varchar(50)" json:"contact_number"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	Restaurant    Restaurant     `gorm:"foreignKey:RestaurantID" json:"-"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (b *Branch) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+// BranchStats summarizes the activity of a single branch
+type BranchStats struct {
+	BranchID          uuid.UUID `json:"branch_id"`
+	Name              string    `json:"name"`
+	TotalEvents       int       `json:"total_events"`
+	UpcomingEvents    int       `json:"upcoming_events"`
+	MealsServed       int       `json:"meals_served"`
+	VolunteersEngaged int       `json:"volunteers_engaged"`
+}
@@ -0,0 +1,208 @@
+package domain
+
+// ErrorKind is the class of failure an Error belongs to, the HTTP adapter maps each kind
+// to a status code
+type ErrorKind string
+
+const (
+	ErrorKindNotFound     ErrorKind = "not_found"
+	ErrorKindConflict     ErrorKind = "conflict"
+	ErrorKindValidation   ErrorKind = "validation"
+	ErrorKindForbidden    ErrorKind = "forbidden"
+	ErrorKindUnauthorized ErrorKind = "unauthorized"
+	ErrorKindRateLimited  ErrorKind = "rate_limited"
+	ErrorKindInternal     ErrorKind = "internal" // a bug, the message is safe to show but the client cannot fix it
+)
+
+// Error is a failure the client can act on. Code is stable and meant for programs, Message
+// is meant for people. Any other error is internal and its text is never shown to clients.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is matches errors with the same code, so errors.Is finds a sentinel below even when the
+// message was written for the occasion
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+func NewNotFoundError(code, message string) *Error {
+	return &Error{Kind: ErrorKindNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code, message string) *Error {
+	return &Error{Kind: ErrorKindConflict, Code: code, Message: message}
+}
+
+func NewValidationError(code, message string) *Error {
+	return &Error{Kind: ErrorKindValidation, Code: code, Message: message}
+}
+
+func NewForbiddenError(code, message string) *Error {
+	return &Error{Kind: ErrorKindForbidden, Code: code, Message: message}
+}
+
+func NewUnauthorizedError(code, message string) *Error {
+	return &Error{Kind: ErrorKindUnauthorized, Code: code, Message: message}
+}
+
+func NewRateLimitedError(code, message string) *Error {
+	return &Error{Kind: ErrorKindRateLimited, Code: code, Message: message}
+}
+
+func NewInternalError(code, message string) *Error {
+	return &Error{Kind: ErrorKindInternal, Code: code, Message: message}
+}
+
+// Codes shared by errors whose message is written for the occasion
+const (
+	ErrCodeInvalidID         = "invalid_id"
+	ErrCodeInvalidRequest    = "invalid_request"
+	ErrCodePermissionDenied  = "permission_denied"
+	ErrCodeTooManyAttempts   = "too_many_attempts"
+	ErrCodeInsufficientStock = "insufficient_stock"
+)
+
+// Records that do not exist, or that the caller may not know about
+var (
+	ErrUserNotFound            = NewNotFoundError("user_not_found", "user not found")
+	ErrRestaurantNotFound      = NewNotFoundError("restaurant_not_found", "restaurant not found")
+	ErrOrganizationNotFound    = NewNotFoundError("organization_not_found", "organization not found")
+	ErrBranchNotFound          = NewNotFoundError("branch_not_found", "branch not found")
+	ErrEventNotFound           = NewNotFoundError("event_not_found", "event not found")
+	ErrEventHostNotFound       = NewNotFoundError("host_not_found", "host not found")
+	ErrVolunteerNotFound       = NewNotFoundError("volunteer_not_found", "volunteer not found")
+	ErrApplicationNotFound     = NewNotFoundError("application_not_found", "application not found")
+	ErrInventoryItemNotFound   = NewNotFoundError("inventory_item_not_found", "inventory item not found")
+	ErrMealLogEntryNotFound    = NewNotFoundError("meal_log_entry_not_found", "meal log entry not found")
+	ErrDocumentNotFound        = NewNotFoundError("document_not_found", "document not found")
+	ErrInvitationNotFound      = NewNotFoundError("invitation_not_found", "invitation not found")
+	ErrMemberNotFound          = NewNotFoundError("member_not_found", "member not found")
+	ErrAPIKeyNotFound          = NewNotFoundError("api_key_not_found", "API key not found")
+	ErrSessionNotFound         = NewNotFoundError("session_not_found", "session not found")
+	ErrIdentityNotFound        = NewNotFoundError("identity_not_found", "identity not found")
+	ErrUnknownIdentityProvider = NewNotFoundError("unknown_identity_provider", "unknown identity provider")
+)
+
+// Authentication
+var (
+	ErrAuthenticationRequired   = NewUnauthorizedError("authentication_required", "authentication required")
+	ErrInvalidCredentials       = NewUnauthorizedError("invalid_credentials", "invalid credentials")
+	ErrInvalidToken             = NewUnauthorizedError("invalid_token", "token invalid or expired")
+	ErrInvalidSession           = NewUnauthorizedError("invalid_session", "invalid or expired session")
+	ErrInvalidRefreshToken      = NewUnauthorizedError("invalid_refresh_token", "refresh token invalid or expired")
+	ErrRefreshTokenReused       = NewUnauthorizedError("refresh_token_reused", "refresh token was already used, please sign in again")
+	ErrInvalidChallenge         = NewUnauthorizedError("invalid_challenge", "sign-in challenge is invalid or has expired, sign in again")
+	ErrInvalidTwoFactorCode     = NewUnauthorizedError("invalid_two_factor_code", "invalid two-factor code")
+	ErrInvalidMagicLink         = NewUnauthorizedError("invalid_magic_link", "sign-in link is invalid, has expired or was requested on another device")
+	ErrInvalidAPIKey            = NewUnauthorizedError("invalid_api_key", "invalid or revoked API key")
+	ErrPermissionDenied         = NewForbiddenError(ErrCodePermissionDenied, "you don't have permission to access this resource")
+	ErrIncorrectPassword        = NewForbiddenError("incorrect_password", "current password is incorrect")
+	ErrInvalidCSRFToken         = NewForbiddenError("invalid_csrf_token", "invalid or missing CSRF token")
+	ErrAccountLocked            = NewForbiddenError("account_locked", "this account is temporarily locked after too many failed sign-ins, check your email to unlock it")
+	ErrAccountSuspended         = NewForbiddenError("account_suspended", "this account has been suspended")
+	ErrIdentityProviderRejected = NewUnauthorizedError("identity_provider_rejected", "the identity provider could not confirm the sign-in, please try again")
+	ErrProviderEmailUnverified  = NewForbiddenError("provider_email_unverified", "the identity provider has not verified your email address")
+	ErrIdentityLinkRequired     = NewConflictError("identity_link_required", "an account with this email address exists, sign in with your password and verify the address to link it")
+	ErrUsernameUnavailable      = NewConflictError("username_unavailable", "could not find a free username, register with a password instead")
+	ErrTooManyFailedSignIns     = NewRateLimitedError(ErrCodeTooManyAttempts, "too many failed sign-ins from this address, try again later")
+	ErrTooManyMagicLinks        = NewRateLimitedError("too_many_magic_links", "too many sign-in links requested from this address, try again later")
+)
+
+// Accounts
+var (
+	ErrEmailTaken                 = NewConflictError("email_taken", "user with this email already exists")
+	ErrUsernameTaken              = NewConflictError("username_taken", "username already taken")
+	ErrSameEmail                  = NewValidationError("same_email", "new email is the same as the current one")
+	ErrAdminPasswordTooShort      = NewValidationError("password_too_short", "admin password must be at least 12 characters long")
+	ErrInvalidOrganizationType    = NewValidationError("invalid_organization_type", "invalid organization type")
+	ErrInvalidTypeChange          = NewValidationError("invalid_type_change", "only regular and admin accounts can change type")
+	ErrInvalidUserType            = NewInternalError("invalid_user_type", "the signed-in user could not be loaded")
+	ErrEmailAlreadyVerified       = NewConflictError("email_already_verified", "email address is already verified")
+	ErrVerificationEmailThrottled = NewRateLimitedError("verification_email_throttled", "a verification email was sent recently, please wait a minute before asking for another one")
+	ErrInvalidVerificationLink    = NewValidationError("invalid_verification_link", "verification link is invalid or has expired")
+	ErrInvalidConfirmationLink    = NewValidationError("invalid_confirmation_link", "confirmation link is invalid or has expired")
+	ErrInvalidResetLink           = NewValidationError("invalid_reset_link", "reset link is invalid or has expired")
+	ErrInvalidUnlockLink          = NewValidationError("invalid_unlock_link", "unlock link is invalid or has expired")
+	ErrAdminNotSuspendable        = NewForbiddenError("admin_not_suspendable", "admin accounts cannot be suspended")
+	ErrProfileNotOwned            = NewForbiddenError("profile_not_owned", "profile does not belong to user")
+	ErrUnsupportedProfileType     = NewValidationError("unsupported_profile_type", "unsupported profile type")
+	ErrDeletionAlreadyScheduled   = NewConflictError("deletion_already_scheduled", "account deletion is already scheduled")
+	ErrDeletionNotScheduled       = NewConflictError("deletion_not_scheduled", "no account deletion is scheduled")
+	ErrAdminNotDeletable          = NewForbiddenError("admin_not_deletable", "admin accounts cannot be deleted")
+	ErrOwnerNotDeletable          = NewConflictError("owner_not_deletable", "transfer or close your organization before deleting your account")
+)
+
+// Two-factor authentication
+var (
+	ErrTwoFactorAlreadyEnabled = NewConflictError("two_factor_already_enabled", "two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = NewConflictError("two_factor_not_enabled", "two-factor authentication is not enabled")
+	ErrTwoFactorSetupRequired  = NewConflictError("two_factor_setup_required", "start the two-factor setup first")
+	ErrTwoFactorRequired       = NewForbiddenError("two_factor_required", "two-factor authentication is required for organization owners")
+	ErrTwoFactorEnrollment     = NewForbiddenError("two_factor_enrollment_required", "please enable two-factor authentication first")
+	ErrEmailVerificationNeeded = NewForbiddenError("email_verification_required", "please verify your email address first")
+)
+
+// Impersonation
+var (
+	ErrAdminNotImpersonable     = NewForbiddenError("admin_not_impersonable", "admin accounts cannot be impersonated")
+	ErrSuspendedNotImpersonable = NewForbiddenError("suspended_not_impersonable", "suspended accounts cannot be impersonated")
+	ErrCurrentSession           = NewValidationError("current_session", "use logout to end the current session")
+	ErrNotImpersonating         = NewConflictError("not_impersonating", "this session is not an impersonation")
+	ErrForbiddenImpersonating   = NewForbiddenError("forbidden_while_impersonating", "this action is not available while impersonating a user")
+)
+
+// Restaurants, their members and verification
+var (
+	ErrNotAMember              = NewForbiddenError("not_a_member", "you are not a member of this restaurant")
+	ErrNoMembership            = NewForbiddenError("no_membership", "you are not a member of any restaurant")
+	ErrRestaurantRequired      = NewValidationError("restaurant_required", "you are a member of several restaurants, select one with the X-Restaurant-ID header")
+	ErrOwnerRoleImmutable      = NewForbiddenError("owner_role_immutable", "the owner's role cannot be changed")
+	ErrOwnerNotRemovable       = NewForbiddenError("owner_not_removable", "the owner cannot be removed")
+	ErrAlreadyMember           = NewConflictError("already_member", "this user is already a member of the restaurant")
+	ErrAlreadyJoined           = NewConflictError("already_joined", "you are already a member of this restaurant")
+	ErrInvitationEmailMismatch = NewForbiddenError("invitation_email_mismatch", "this invitation was sent to a different email address")
+	ErrInvitationAccountType   = NewForbiddenError("invitation_account_type", "only restaurant and regular accounts can join a restaurant")
+	ErrInvalidInvitation       = NewValidationError("invalid_invitation", "invitation is invalid or has expired")
+	ErrBranchNotInRestaurant   = NewValidationError("branch_not_in_restaurant", "branch does not belong to this restaurant")
+	ErrBranchRequired          = NewValidationError("branch_required", "branch_id is required for restaurants with several branches")
+	ErrLastBranch              = NewConflictError("last_branch", "a restaurant must keep at least one branch")
+	ErrBranchHasUpcomingEvents = NewConflictError("branch_has_upcoming_events", "branch still has upcoming events")
+	ErrNoBusinessEmail         = NewValidationError("no_business_email", "restaurant has no business email")
+	ErrBusinessEmailVerified   = NewConflictError("business_email_already_verified", "business email is already verified")
+	ErrBusinessEmailUnverified = NewForbiddenError("business_email_unverified", "the business email must be verified before publishing events")
+	ErrRestaurantVerified      = NewConflictError("restaurant_already_verified", "restaurant is already verified")
+	ErrVerifiedDocumentsLocked = NewConflictError("verified_documents_locked", "documents of a verified restaurant cannot be deleted")
+	ErrNoDocumentsSubmitted    = NewConflictError("no_documents_submitted", "restaurant has not submitted any documents")
+	ErrFileRequired            = NewValidationError("file_required", "file is required")
+	ErrFileTooLarge            = NewValidationError("file_too_large", "file is too large")
+)
+
+// Events, meals and inventory
+var (
+	ErrAlreadyHost              = NewConflictError("already_host", "the organization already hosts this event")
+	ErrAlreadyCoHost            = NewConflictError("already_co_host", "the organization already co-hosts this event")
+	ErrNegativeMealsServed      = NewValidationError("negative_meals_served", "meals served cannot be negative")
+	ErrInvalidMealCount         = NewValidationError("invalid_meal_count", "meal count must be greater than zero")
+	ErrMealEntryNotInEvent      = NewNotFoundError("meal_log_entry_not_found", "meal log entry does not belong to this event")
+	ErrCorrectionOfCorrection   = NewConflictError("correction_of_correction", "corrections cannot be corrected, record a new entry instead")
+	ErrEntryAlreadyCorrected    = NewConflictError("entry_already_corrected", "this entry has already been corrected")
+	ErrCannotRecordMeals        = NewForbiddenError("cannot_record_meals", "restaurant cannot record meals for this event")
+	ErrEntryOfOtherHost         = NewForbiddenError("entry_of_other_host", "meal log entries can only be corrected by the host they are credited to")
+	ErrNegativeQuantity         = NewValidationError("negative_quantity", "quantity cannot be negative")
+	ErrInvalidQuantity          = NewValidationError("invalid_quantity", "quantity must be greater than zero")
+	ErrItemNotInEventRestaurant = NewValidationError("item_not_in_event_restaurant", "inventory item does not belong to the event's restaurant")
+	ErrApplicationApproved      = NewConflictError("application_approved", "approved applications cannot be changed")
+	ErrAlreadyApplied           = NewConflictError("already_applied", "you have already applied for this event")
+	ErrAlreadyAssigned          = NewConflictError("already_assigned", "you are already assigned to this event")
+	ErrEventNotAccepting        = NewConflictError("event_not_accepting_volunteers", "this event is not accepting volunteers")
+	ErrEventFull                = NewConflictError("event_full", "this event has reached its volunteer capacity")
+	ErrCheckInUnavailable       = NewConflictError("check_in_unavailable", "check-in is only available for active events")
+)
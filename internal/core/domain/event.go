@@ -19,6 +19,7 @@ const (
 type Event struct {
 	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
 	RestaurantID  uuid.UUID      `gorm:"type:uuid;not null" json:"restaurant_id"`
+	BranchID      *uuid.UUID     `gorm:"type:uuid;index" json:"branch_id"`
 	Title         string         `gorm:"type:varchar(255);not null" json:"title"`
 	Description   string         `gorm:"type:text" json:"description"`
 	Date          time.Time      `gorm:"not null" json:"date"`
@@ -34,6 +35,7 @@ type Event struct {
 	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 	Restaurant    Restaurant     `gorm:"foreignKey:RestaurantID" json:"-"`
+	Hosts         []*EventHost   `gorm:"-" json:"hosts,omitempty"` // co-hosts, loaded with the event
 }
 
 // BeforeCreate will set a UUID rather than numeric ID
@@ -44,6 +46,86 @@ func (e *Event) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// HostCan reports whether the restaurant may act on the event with the permission. The
+// restaurant that created the event may do everything, co-hosts what they were granted.
+func (e *Event) HostCan(restaurantID uuid.UUID, permission Permission) bool {
+	if e.RestaurantID == restaurantID {
+		return true
+	}
+
+	for _, h := range e.Hosts {
+		if h.RestaurantID == restaurantID {
+			return h.Can(permission)
+		}
+	}
+	return false
+}
+
+// EventHost is an organization co-hosting an event created by another one, for instance
+// an NGO providing the volunteers for an iftar a restaurant provides the food for
+type EventHost struct {
+	ID                    uuid.UUID   `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID               uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_event_host" json:"event_id"`
+	RestaurantID          uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_event_host;index" json:"restaurant_id"`
+	CanEditDetails        bool        `gorm:"default:false" json:"can_edit_details"`
+	CanReviewApplications bool        `gorm:"default:false" json:"can_review_applications"`
+	CanRecordMeals        bool        `gorm:"default:false" json:"can_record_meals"`
+	CreatedAt             time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt             time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+	Restaurant            *Restaurant `gorm:"foreignKey:RestaurantID" json:"restaurant,omitempty"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (h *EventHost) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
+
+// Can reports whether the co-host was granted the permission on the event, co-hosts can
+// always view the events they host
+func (h *EventHost) Can(permission Permission) bool {
+	switch permission {
+	case PermissionViewEvents:
+		return true
+	case PermissionManageEvents:
+		return h.CanEditDetails
+	case PermissionReviewApplications:
+		return h.CanReviewApplications
+	case PermissionRecordMeals:
+		return h.CanRecordMeals
+	}
+	return false
+}
+
+// UpdateEventRequest holds the fields of an event its restaurant may edit, the status, the
+// guest count and the meal count are changed through their own endpoints
+type UpdateEventRequest struct {
+	BranchID      *uuid.UUID `json:"branch_id"`
+	Title         string     `json:"title" binding:"required,max=255"`
+	Description   string     `json:"description"`
+	Date          time.Time  `json:"date" binding:"required"`
+	StartTime     time.Time  `json:"start_time" binding:"required"`
+	EndTime       time.Time  `json:"end_time" binding:"required"`
+	Location      string     `json:"location" binding:"max=255"`
+	MaxGuests     int        `json:"max_guests" binding:"gte=0"`
+	MaxVolunteers int        `json:"max_volunteers" binding:"gte=0"`
+}
+
+type AddEventHostRequest struct {
+	RestaurantID          uuid.UUID `json:"restaurant_id" binding:"required"`
+	CanEditDetails        bool      `json:"can_edit_details"`
+	CanReviewApplications bool      `json:"can_review_applications"`
+	CanRecordMeals        bool      `json:"can_record_meals"`
+}
+
+type UpdateEventHostRequest struct {
+	CanEditDetails        bool `json:"can_edit_details"`
+	CanReviewApplications bool `json:"can_review_applications"`
+	CanRecordMeals        bool `json:"can_record_meals"`
+}
+
 type VolunteerApplication struct {
 	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
 	EventID     uuid.UUID      `gorm:"type:uuid;not null" json:"event_id"`
@@ -85,3 +167,32 @@ func (ev *EventVolunteer) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// MealLogEntry is an append-only record of meals served during an event. Totals on
+// Event and Restaurant are derived from the log; mistakes are fixed by adding a
+// compensating entry with a negative count rather than editing existing ones. Meals are
+// credited to the host that recorded them.
+type MealLogEntry struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"event_id"`
+	RestaurantID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	RecordedBy      uuid.UUID  `gorm:"type:uuid;not null" json:"recorded_by"`
+	Count           int        `gorm:"not null" json:"count"`
+	Note            string     `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CorrectsEntryID *uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_meal_log_entry_correction" json:"corrects_entry_id,omitempty"` // an entry is reversed at most once
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	Event           Event      `gorm:"foreignKey:EventID" json:"-"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (m *MealLogEntry) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsCorrection reports whether the entry compensates an earlier one
+func (m *MealLogEntry) IsCorrection() bool {
+	return m.CorrectsEntryID != nil
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExternalIdentity links an account at an OpenID Connect provider to a user, so the user can
+// sign in through that provider
+type ExternalIdentity struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Provider  string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_external_identity" json:"provider"`
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_external_identity" json:"-"` // the provider's ID of the account
+	Email     string    `gorm:"type:varchar(255)" json:"email"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (i *ExternalIdentity) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// ExternalClaims is what a provider's verified ID token says about the user
+type ExternalClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCAuthorization starts a sign-in at a provider. The client is sent to URL and must keep
+// State, Nonce and CodeVerifier to itself until the provider redirects back.
+type OIDCAuthorization struct {
+	URL          string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// OIDCCallback is what completes a sign-in at a provider, the code the provider redirected
+// back with and the secrets kept since OIDCAuthorization
+type OIDCCallback struct {
+	Code         string
+	Nonce        string
+	CodeVerifier string
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InventoryItem struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	RestaurantID uuid.UUID      `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	Name         string         `gorm:"type:varchar(255);not null" json:"name" binding:"required"`
+	Quantity     float64        `gorm:"not null;default:0" json:"quantity" binding:"gte=0"`
+	Unit         string         `gorm:"type:varchar(50);not null" json:"unit" binding:"required"`
+	UnitCost     float64        `gorm:"default:0" json:"unit_cost" binding:"gte=0"`
+	ExpiryDate   *time.Time     `gorm:"index" json:"expiry_date,omitempty"`
+	Donor        string         `gorm:"type:varchar(255)" json:"donor,omitempty"` // empty when purchased by the restaurant
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	Restaurant   Restaurant     `gorm:"foreignKey:RestaurantID" json:"-"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (i *InventoryItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsDonated reports whether the item was given to the restaurant rather than purchased
+func (i *InventoryItem) IsDonated() bool {
+	return i.Donor != ""
+}
+
+// ExpiresWithin reports whether the item expires before now+d. Items without an expiry date never do.
+func (i *InventoryItem) ExpiresWithin(d time.Duration) bool {
+	if i.ExpiryDate == nil {
+		return false
+	}
+	return i.ExpiryDate.Before(time.Now().Add(d))
+}
+
+type InventoryConsumption struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	InventoryItemID uuid.UUID      `gorm:"type:uuid;not null;index" json:"inventory_item_id"`
+	EventID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"event_id"`
+	Quantity        float64        `gorm:"not null" json:"quantity"`
+	UnitCost        float64        `gorm:"default:0" json:"unit_cost"` // copied from the item at consumption time
+	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	InventoryItem   InventoryItem  `gorm:"foreignKey:InventoryItemID" json:"inventory_item,omitempty"`
+	Event           Event          `gorm:"foreignKey:EventID" json:"-"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (ic *InventoryConsumption) BeforeCreate(tx *gorm.DB) error {
+	if ic.ID == uuid.Nil {
+		ic.ID = uuid.New()
+	}
+	return nil
+}
+
+type IngredientUsage struct {
+	InventoryItemID uuid.UUID `json:"inventory_item_id"`
+	Name            string    `json:"name"`
+	Unit            string    `json:"unit"`
+	Quantity        float64   `json:"quantity"`
+	Cost            float64   `json:"cost"`
+	Donated         bool      `json:"donated"`
+}
+
+type EventInventorySummary struct {
+	EventID     uuid.UUID         `json:"event_id"`
+	Title       string            `json:"title"`
+	MealsServed int               `json:"meals_served"`
+	TotalCost   float64           `json:"total_cost"`
+	CostPerMeal float64           `json:"cost_per_meal"`
+	Ingredients []IngredientUsage `json:"ingredients"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginAttempt records a failed sign-in, kept as an audit trail of brute-force attempts
+type LoginAttempt struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID    *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"` // nil when no account has the email
+	Email     string     `gorm:"type:varchar(255);not null;index" json:"email"`
+	IPAddress string     `gorm:"type:varchar(45);index" json:"ip_address"`
+	UserAgent string     `gorm:"type:varchar(255)" json:"user_agent"`
+	Reason    string     `gorm:"type:varchar(50);not null" json:"reason"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (a *LoginAttempt) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MembershipRole is the role of a user within a restaurant's team. It narrows down the
+// permissions the restaurant user type grants for that restaurant.
+type MembershipRole string
+
+const (
+	MembershipRoleOwner   MembershipRole = "owner"
+	MembershipRoleManager MembershipRole = "manager"
+	MembershipRoleStaff   MembershipRole = "staff"
+)
+
+var membershipPermissions = map[MembershipRole][]Permission{
+	MembershipRoleOwner: {
+		PermissionViewRestaurant,
+		PermissionViewEvents,
+		PermissionManageRestaurant,
+		PermissionManageMembers,
+		PermissionManageEvents,
+		PermissionReviewApplications,
+		PermissionRecordMeals,
+		PermissionManageInventory,
+	},
+	MembershipRoleManager: {
+		PermissionViewRestaurant,
+		PermissionViewEvents,
+		PermissionManageEvents,
+		PermissionReviewApplications,
+		PermissionRecordMeals,
+		PermissionManageInventory,
+	},
+	MembershipRoleStaff: {
+		PermissionViewRestaurant,
+		PermissionViewEvents,
+		PermissionRecordMeals,
+	},
+}
+
+// Can reports whether the membership role grants the permission
+func (r MembershipRole) Can(permission Permission) bool {
+	for _, p := range membershipPermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid reports whether the role is a known membership role
+func (r MembershipRole) IsValid() bool {
+	_, ok := membershipPermissions[r]
+	return ok
+}
+
+type RestaurantMember struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	RestaurantID uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_restaurant_member" json:"restaurant_id"`
+	UserID       uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_restaurant_member;index" json:"user_id"`
+	Role         MembershipRole `gorm:"type:varchar(20);not null" json:"role"`
+	BranchID     *uuid.UUID     `gorm:"type:uuid" json:"branch_id,omitempty"` // nil grants access to every branch
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	Restaurant   *Restaurant    `gorm:"foreignKey:RestaurantID" json:"restaurant,omitempty"`
+	User         *User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (m *RestaurantMember) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// CanAccessBranch reports whether the member may act on the branch, members that are not
+// limited to a branch may act on all of them
+func (m *RestaurantMember) CanAccessBranch(branchID *uuid.UUID) bool {
+	if m.BranchID == nil {
+		return true
+	}
+	return branchID != nil && *branchID == *m.BranchID
+}
+
+type RestaurantInvitation struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	RestaurantID uuid.UUID      `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	Email        string         `gorm:"type:varchar(255);not null;index" json:"email"`
+	Role         MembershipRole `gorm:"type:varchar(20);not null" json:"role"`
+	BranchID     *uuid.UUID     `gorm:"type:uuid" json:"branch_id,omitempty"`
+	TokenHash    string         `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	InvitedBy    uuid.UUID      `gorm:"type:uuid;not null" json:"invited_by"`
+	ExpiresAt    time.Time      `gorm:"not null" json:"expires_at"`
+	AcceptedAt   *time.Time     `json:"accepted_at,omitempty"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	Restaurant   *Restaurant    `gorm:"foreignKey:RestaurantID" json:"restaurant,omitempty"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (i *RestaurantInvitation) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsPending reports whether the invitation can still be accepted
+func (i *RestaurantInvitation) IsPending() bool {
+	return i.AcceptedAt == nil && time.Now().Before(i.ExpiresAt)
+}
+
+type InviteMemberRequest struct {
+	Email    string         `json:"email" binding:"required,email"`
+	Role     MembershipRole `json:"role" binding:"required,oneof=manager staff"`
+	BranchID *uuid.UUID     `json:"branch_id"`
+}
+
+// UpdateMemberRequest replaces the role and branch of a member, a nil branch grants
+// access to every branch
+type UpdateMemberRequest struct {
+	Role     MembershipRole `json:"role" binding:"required,oneof=manager staff"`
+	BranchID *uuid.UUID     `json:"branch_id"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// StaffRegisterRequest creates an account for someone invited to a restaurant, the
+// email address is taken from the invitation
+type StaffRegisterRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+// OrganizationType is the kind of organization hosting events. Restaurants were the first
+// kind of host, so every organization is stored as a Restaurant and acts through the same
+// memberships, branches and events.
+type OrganizationType string
+
+const (
+	OrganizationTypeRestaurant       OrganizationType = "restaurant"
+	OrganizationTypeMosque           OrganizationType = "mosque"
+	OrganizationTypeNGO              OrganizationType = "ngo"
+	OrganizationTypeCommunityKitchen OrganizationType = "community_kitchen"
+	OrganizationTypeSchool           OrganizationType = "school"
+)
+
+// IsValid reports whether the type is a known organization type
+func (t OrganizationType) IsValid() bool {
+	switch t {
+	case OrganizationTypeRestaurant, OrganizationTypeMosque, OrganizationTypeNGO,
+		OrganizationTypeCommunityKitchen, OrganizationTypeSchool:
+		return true
+	}
+	return false
+}
+
+// Organization is any host of events, see OrganizationType
+type Organization = Restaurant
+
+// OrganizationRegisterRequest registers an organization of any type together with the
+// account of its owner
+type OrganizationRegisterRequest struct {
+	Username         string           `json:"username" binding:"required"`
+	Email            string           `json:"email" binding:"required,email"`
+	Password         string           `json:"password" binding:"required,min=6"`
+	OrganizationType OrganizationType `json:"organization_type" binding:"required,oneof=restaurant mosque ngo community_kitchen school"`
+	Name             string           `json:"name" binding:"required"`
+	BusinessEmail    string           `json:"business_email" binding:"required,email"`
+	ContactNumber    string           `json:"contact_number" binding:"required"`
+	Address          string           `json:"address" binding:"required"`
+}
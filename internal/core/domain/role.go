@@ -0,0 +1,68 @@
+package domain
+
+// Permission is an action a user is allowed to perform. Permissions are derived from
+// the user type, which is embedded in session tokens as the role claim. Restaurant users
+// are further limited by their MembershipRole in the restaurant they act for.
+type Permission string
+
+const (
+	PermissionManageProfile      Permission = "profile:manage"
+	PermissionViewRestaurant     Permission = "restaurant:view"
+	PermissionViewEvents         Permission = "events:view"
+	PermissionManageRestaurant   Permission = "restaurant:manage"
+	PermissionManageMembers      Permission = "restaurant:members"
+	PermissionManageEvents       Permission = "events:manage"
+	PermissionReviewApplications Permission = "applications:review"
+	PermissionRecordMeals        Permission = "meals:record"
+	PermissionManageInventory    Permission = "inventory:manage"
+	PermissionApplyForEvents     Permission = "events:apply"
+	PermissionCheckIn            Permission = "events:check_in"
+	PermissionAdminister         Permission = "platform:administer"
+)
+
+var rolePermissions = map[UserType][]Permission{
+	UserTypeRegular: {
+		PermissionManageProfile,
+	},
+	UserTypeRestaurant: {
+		PermissionManageProfile,
+		PermissionViewRestaurant,
+		PermissionViewEvents,
+		PermissionManageRestaurant,
+		PermissionManageMembers,
+		PermissionManageEvents,
+		PermissionReviewApplications,
+		PermissionRecordMeals,
+		PermissionManageInventory,
+	},
+	UserTypeVolunteer: {
+		PermissionManageProfile,
+		PermissionApplyForEvents,
+		PermissionCheckIn,
+	},
+	UserTypeAdmin: {
+		PermissionManageProfile,
+		PermissionAdminister,
+	},
+}
+
+// Permissions returns the permissions granted to the role
+func (t UserType) Permissions() []Permission {
+	return rolePermissions[t]
+}
+
+// Can reports whether the role grants the permission
+func (t UserType) Can(permission Permission) bool {
+	for _, p := range rolePermissions[t] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid reports whether the role is a known user type
+func (t UserType) IsValid() bool {
+	_, ok := rolePermissions[t]
+	return ok
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a sign-in on one device. The tokens issued for it carry its ID, so revoking
+// the session signs out that device only. Each refresh rotates the refresh token of the
+// session, a session is the family of all refresh tokens issued for it.
+type Session struct {
+	ID               uuid.UUID `json:"id"`
+	UserID           uuid.UUID `json:"user_id"`
+	UserAgent        string    `json:"user_agent"`
+	IPAddress        string    `json:"ip_address"`
+	CreatedAt        time.Time `json:"created_at"`
+	LastSeenAt       time.Time `json:"last_seen_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshTokenHash string    `json:"-"` // digest of the only refresh token still valid
+
+	// RotatedTokenHashes are the digests of the refresh tokens the session handed out
+	// before, presenting one of them again means it leaked
+	RotatedTokenHashes []string `json:"-"`
+
+	// ImpersonatorID is the administrator who opened the session to see what the user
+	// sees. Such sessions cannot be refreshed and cannot change the account's security.
+	ImpersonatorID      *uuid.UUID `json:"impersonator_id,omitempty"`
+	ImpersonationReason string     `json:"impersonation_reason,omitempty"`
+}
+
+// IsImpersonated reports whether an administrator opened the session on the user's behalf
+func (s *Session) IsImpersonated() bool {
+	return s.ImpersonatorID != nil
+}
+
+// DeviceInfo describes the client a session is opened from
+type DeviceInfo struct {
+	UserAgent string
+	IPAddress string
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecoveryCode is a single-use code that stands in for a TOTP code when the authenticator
+// is lost. Only its hash is stored.
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (r *RecoveryCode) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// SecurityPolicy holds the platform-wide security settings administrators control.
+// There is a single row.
+type SecurityPolicy struct {
+	ID                    uint      `gorm:"primaryKey" json:"-"`
+	RequireOwnerTwoFactor bool      `gorm:"not null;default:false" json:"require_owner_two_factor"` // organization owners must enroll in 2FA
+	UpdatedAt             time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type UpdateSecurityPolicyRequest struct {
+	RequireOwnerTwoFactor *bool `json:"require_owner_two_factor" binding:"required"`
+}
+
+// TwoFactorSetup is what an authenticator app needs to enroll, OTPAuthURL is usually shown
+// as a QR code
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+// TwoFactorChallenge is returned instead of tokens when the password was right but the
+// account also requires a TOTP code
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type TwoFactorSetupRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Code            string `json:"code" binding:"required"` // a TOTP code or a recovery code
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`         // sent as a cookie after a provider sign-in
+	Code           string `json:"code" binding:"required"` // a TOTP code or a recovery code
+}
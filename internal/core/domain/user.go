@@ -11,19 +11,53 @@ type UserType string
 
 const (
 	UserTypeRegular    UserType = "regular"
-	UserTypeRestaurant UserType = "restaurant"
+	UserTypeRestaurant UserType = "restaurant" // member of an organization of any type
 	UserTypeVolunteer  UserType = "volunteer"
+	UserTypeAdmin      UserType = "admin"
 )
 
 type User struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	Username  string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"username"`
-	Email     string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	Password  string         `gorm:"type:varchar(255);not null" json:"-"`
-	Type      UserType       `gorm:"type:varchar(20);not null;default:'volunteer'" json:"user_type"`
-	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Username           string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"username"`
+	Email              string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Password           string         `gorm:"type:varchar(255);not null" json:"-"`
+	Type               UserType       `gorm:"type:varchar(20);not null;default:'volunteer'" json:"user_type"`
+	EmailVerifiedAt    *time.Time     `json:"email_verified_at"`
+	PendingEmail       string         `gorm:"type:varchar(255)" json:"pending_email,omitempty"` // waiting for its verification to replace Email
+	SuspendedAt        *time.Time     `json:"suspended_at,omitempty"`
+	LockedUntil        *time.Time     `json:"locked_until,omitempty"`    // set after too many failed sign-ins
+	TOTPSecret         string         `gorm:"type:varchar(64)" json:"-"` // set during setup, in use once TwoFactorEnabledAt is set
+	TwoFactorEnabledAt *time.Time     `json:"two_factor_enabled_at"`
+	SuspensionReason   string         `gorm:"type:varchar(255)" json:"suspension_reason,omitempty"`
+	DeletionDueAt      *time.Time     `gorm:"index" json:"deletion_due_at,omitempty"` // the account is erased at this time unless the user cancels
+	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// IsSuspended reports whether an administrator has blocked the account
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// IsLocked reports whether sign-in is blocked after too many failed attempts
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && time.Now().Before(*u.LockedUntil)
+}
+
+// IsTwoFactorEnabled reports whether sign-ins also require a TOTP code
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+// IsDeletionScheduled reports whether the user asked for their account to be deleted
+func (u *User) IsDeletionScheduled() bool {
+	return u.DeletionDueAt != nil
+}
+
+// IsEmailVerified reports whether the user proved they own their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
@@ -34,27 +68,51 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 }
 
 type Restaurant struct {
-	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	UserID        uuid.UUID      `gorm:"type:uuid;uniqueIndex;not null" json:"user_id"`
-	Name          string         `gorm:"type:varchar(255);not null" json:"name"`
-	Address       string         `gorm:"type:varchar(255)" json:"address"`
-	ContactNumber string         `gorm:"type:varchar(50)" json:"contact_number"`
-	TotalEvents   int            `gorm:"default:0" json:"total_events"`
-	MealsServed   int            `gorm:"default:0" json:"meals_served"`
-	Rating        float64        `gorm:"default:0" json:"rating"`
-	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
-	User          User           `gorm:"foreignKey:UserID" json:"-"`
+	ID                      uuid.UUID          `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID                  uuid.UUID          `gorm:"type:uuid;uniqueIndex;not null" json:"user_id"`
+	Name                    string             `gorm:"type:varchar(255);not null" json:"name"`
+	Address                 string             `gorm:"type:varchar(255)" json:"address"`
+	ContactNumber           string             `gorm:"type:varchar(50)" json:"contact_number"`
+	BusinessEmail           string             `gorm:"type:varchar(255)" json:"business_email"`
+	BusinessEmailVerifiedAt *time.Time         `json:"business_email_verified_at"`
+	OrganizationType        OrganizationType   `gorm:"type:varchar(30);not null;default:'restaurant';index" json:"organization_type"`
+	TotalEvents             int                `gorm:"default:0" json:"total_events"`
+	MealsServed             int                `gorm:"default:0" json:"meals_served"`
+	Rating                  float64            `gorm:"default:0" json:"rating"`
+	VerificationStatus      VerificationStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"verification_status"`
+	VerifiedAt              *time.Time         `json:"verified_at,omitempty"`
+	RejectionReason         string             `gorm:"type:varchar(255)" json:"rejection_reason,omitempty"`
+	CreatedAt               time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt               time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt               gorm.DeletedAt     `gorm:"index" json:"-"`
+	User                    User               `gorm:"foreignKey:UserID" json:"-"`
 }
 
 func (r *Restaurant) BeforeCreate(tx *gorm.DB) error {
 	if r.ID == uuid.Nil {
 		r.ID = uuid.New()
 	}
+	if r.VerificationStatus == "" {
+		r.VerificationStatus = VerificationStatusPending
+	}
+	if r.OrganizationType == "" {
+		r.OrganizationType = OrganizationTypeRestaurant
+	}
 	return nil
 }
 
+// IsBusinessEmailVerified reports whether the restaurant proved it owns its business email,
+// restaurants publish events only once it is verified
+func (r *Restaurant) IsBusinessEmailVerified() bool {
+	return r.BusinessEmailVerifiedAt != nil
+}
+
+// IsVerified reports whether an administrator approved the restaurant, only verified
+// restaurants have their events shown to volunteers
+func (r *Restaurant) IsVerified() bool {
+	return r.VerificationStatus == VerificationStatusVerified
+}
+
 type Volunteer struct {
 	ID               uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
 	UserID           uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
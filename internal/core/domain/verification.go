@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type VerificationStatus string
+
+const (
+	VerificationStatusPending  VerificationStatus = "pending"
+	VerificationStatusVerified VerificationStatus = "verified"
+	VerificationStatusRejected VerificationStatus = "rejected"
+)
+
+type DocumentType string
+
+const (
+	DocumentTypeBusinessLicense DocumentType = "business_license"
+	DocumentTypeFoodSafety      DocumentType = "food_safety_certificate"
+	DocumentTypeIdentity        DocumentType = "identity"
+	DocumentTypeOther           DocumentType = "other"
+)
+
+type RestaurantDocument struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	RestaurantID uuid.UUID      `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	DocumentType DocumentType   `gorm:"type:varchar(50);not null" json:"document_type"`
+	FileName     string         `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType  string         `gorm:"type:varchar(100)" json:"content_type"`
+	Size         int64          `gorm:"not null" json:"size"`
+	StorageKey   string         `gorm:"type:varchar(512);not null" json:"-"`
+	UploadedAt   time.Time      `gorm:"autoCreateTime" json:"uploaded_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	Restaurant   Restaurant     `gorm:"foreignKey:RestaurantID" json:"-"`
+}
+
+// BeforeCreate will set a UUID rather than numeric ID
+func (d *RestaurantDocument) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+type UploadDocumentRequest struct {
+	DocumentType DocumentType `form:"document_type" binding:"required,oneof=business_license food_safety_certificate identity other"`
+}
+
+type RejectRestaurantRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
@@ -4,12 +4,43 @@ import (
 	"context"
 	"time"
 
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/google/uuid"
 )
 
+// TokenCache keeps the sessions of signed in users, one per device. A token is only
+// accepted while the session it was issued for exists.
 type TokenCache interface {
-	StoreToken(ctx context.Context, userID uuid.UUID, token string, expiration time.Duration) error
-	GetToken(ctx context.Context, userID uuid.UUID) (string, error)
-	InvalidateToken(ctx context.Context, userID uuid.UUID) error
-	TokenExists(ctx context.Context, token string) (bool, error)
+	StoreSession(ctx context.Context, session *domain.Session, expiration time.Duration) error
+	GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error)
+	// RotateRefreshToken stores the session with its new refresh token digest, but only if
+	// the digest stored until now is still previousHash. Otherwise another request rotated
+	// the token first and domain.ErrRefreshTokenReused is returned.
+	RotateRefreshToken(ctx context.Context, session *domain.Session, previousHash string, expiration time.Duration) error
+	TouchSession(ctx context.Context, sessionID uuid.UUID, seenAt time.Time) error
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]*domain.Session, error)
+	InvalidateSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+	InvalidateUserSessions(ctx context.Context, userID uuid.UUID) error
+}
+
+// OneTimeTokenStore keeps the hashes of single-use tokens, such as password reset links,
+// until they are consumed or expire. A token is issued for a subject, usually a user, and
+// a subject has at most one live token per purpose.
+type OneTimeTokenStore interface {
+	Store(ctx context.Context, purpose string, tokenHash string, subjectID uuid.UUID, expiration time.Duration) error
+	Consume(ctx context.Context, purpose string, tokenHash string) (uuid.UUID, error)
+}
+
+// AttemptCounter counts attempts per key within a sliding window
+type AttemptCounter interface {
+	Record(ctx context.Context, key string, window time.Duration) error
+	// Recent returns how many attempts happened within the window and when the latest did
+	Recent(ctx context.Context, key string, window time.Duration) (int, time.Time, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// Throttle limits how often an action identified by a key may happen
+type Throttle interface {
+	// Allow reports whether the action may happen now and, if so, blocks it for the interval
+	Allow(ctx context.Context, key string, interval time.Duration) (bool, error)
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
+)
+
+// IdentityProvider signs users in through an OpenID Connect provider with the authorization
+// code flow and PKCE
+type IdentityProvider interface {
+	// AuthCodeURL returns where to send the user to sign in at the provider
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	// Exchange trades the code the provider redirected back with for the claims of a
+	// verified ID token
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.ExternalClaims, error)
+}
@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// Mailer delivers plain text emails to users
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
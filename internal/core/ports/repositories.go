@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 	"github.com/google/uuid"
@@ -14,15 +15,61 @@ type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*domain.User, error)
 	Update(ctx context.Context, tx interface{}, user *domain.User) error
 	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
+	// Purge removes the user for good, Delete only hides it
+	Purge(ctx context.Context, tx interface{}, id uuid.UUID) error
+	// GetDueForDeletion returns the users whose requested deletion is due by the given time
+	GetDueForDeletion(ctx context.Context, before time.Time) ([]*domain.User, error)
+	List(ctx context.Context, filter domain.ListFilter) ([]*domain.User, int, error)
 }
 
 type RestaurantRepository interface {
 	Create(ctx context.Context, tx interface{}, restaurant *domain.Restaurant) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Restaurant, error)
+	// GetByIDForUpdate locks the restaurant row until tx ends
+	GetByIDForUpdate(ctx context.Context, tx interface{}, id uuid.UUID) (*domain.Restaurant, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.Restaurant, error)
 	Update(ctx context.Context, tx interface{}, restaurant *domain.Restaurant) error
 	UpdateStats(ctx context.Context, tx interface{}, id uuid.UUID, totalEvents, mealsServed int, rating float64) error
 	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
+	List(ctx context.Context, filter domain.ListFilter) ([]*domain.Restaurant, int, error)
+	UpdateVerification(ctx context.Context, tx interface{}, id uuid.UUID, status domain.VerificationStatus, reason string) error
+}
+
+type RestaurantDocumentRepository interface {
+	Create(ctx context.Context, tx interface{}, document *domain.RestaurantDocument) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.RestaurantDocument, error)
+	GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.RestaurantDocument, error)
+	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
+}
+
+type BranchRepository interface {
+	Create(ctx context.Context, tx interface{}, branch *domain.Branch) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Branch, error)
+	GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.Branch, error)
+	Update(ctx context.Context, tx interface{}, branch *domain.Branch) error
+	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
+	GetStats(ctx context.Context, restaurantID uuid.UUID) ([]*domain.BranchStats, error)
+}
+
+type RestaurantMemberRepository interface {
+	Create(ctx context.Context, tx interface{}, member *domain.RestaurantMember) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.RestaurantMember, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.RestaurantMember, error)
+	GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.RestaurantMember, error)
+	GetByRestaurantAndUser(ctx context.Context, restaurantID, userID uuid.UUID) (*domain.RestaurantMember, error)
+	UpdateAccess(ctx context.Context, tx interface{}, id uuid.UUID, role domain.MembershipRole, branchID *uuid.UUID) error
+	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
+	DeleteByUserID(ctx context.Context, tx interface{}, userID uuid.UUID) error
+}
+
+type RestaurantInvitationRepository interface {
+	Create(ctx context.Context, tx interface{}, invitation *domain.RestaurantInvitation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.RestaurantInvitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.RestaurantInvitation, error)
+	GetPendingByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.RestaurantInvitation, error)
+	MarkAccepted(ctx context.Context, tx interface{}, id uuid.UUID) error
+	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
+	DeletePending(ctx context.Context, tx interface{}, restaurantID uuid.UUID, email string) error
 }
 
 type VolunteerRepository interface {
@@ -40,14 +87,17 @@ type VolunteerRepository interface {
 type EventRepository interface {
 	Create(ctx context.Context, tx interface{}, event *domain.Event) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Event, error)
-	GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID, status string, limit, offset int) ([]*domain.Event, int, error)
-	GetTodayEvents(ctx context.Context, restaurantID uuid.UUID) ([]*domain.Event, error)
+	// GetByIDForUpdate locks the event row until tx ends, the hosts are not loaded
+	GetByIDForUpdate(ctx context.Context, tx interface{}, id uuid.UUID) (*domain.Event, error)
+	GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID, branchID *uuid.UUID, status string, limit, offset int) ([]*domain.Event, int, error)
+	GetTodayEvents(ctx context.Context, restaurantID uuid.UUID, branchID *uuid.UUID) ([]*domain.Event, error)
 	Update(ctx context.Context, tx interface{}, event *domain.Event) error
 	UpdateStatus(ctx context.Context, tx interface{}, id uuid.UUID, status string) error
 	UpdateGuestCount(ctx context.Context, tx interface{}, id uuid.UUID, count int) error
 	UpdateMealsServed(ctx context.Context, tx interface{}, id uuid.UUID, count int) error
 	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
 	GetUpcomingEvents(ctx context.Context) ([]*domain.Event, error)
+	List(ctx context.Context, filter domain.ListFilter) ([]*domain.Event, int, error)
 }
 
 type VolunteerApplicationRepository interface {
@@ -57,6 +107,7 @@ type VolunteerApplicationRepository interface {
 	GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID, status string) ([]*domain.VolunteerApplication, error)
 	UpdateStatus(ctx context.Context, tx interface{}, id uuid.UUID, status string) error
 	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
+	List(ctx context.Context, filter domain.ListFilter) ([]*domain.VolunteerApplication, int, error)
 }
 
 type EventVolunteerRepository interface {
@@ -68,9 +119,85 @@ type EventVolunteerRepository interface {
 	CountByEventID(ctx context.Context, eventID uuid.UUID) (int, error)
 }
 
+type EventHostRepository interface {
+	Create(ctx context.Context, tx interface{}, host *domain.EventHost) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.EventHost, error)
+	GetByEventID(ctx context.Context, eventID uuid.UUID) ([]*domain.EventHost, error)
+	Update(ctx context.Context, tx interface{}, host *domain.EventHost) error
+	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
+}
+
+type MealLogRepository interface {
+	Create(ctx context.Context, tx interface{}, entry *domain.MealLogEntry) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.MealLogEntry, error)
+	GetByEventID(ctx context.Context, eventID uuid.UUID) ([]*domain.MealLogEntry, error)
+	SumByEventID(ctx context.Context, tx interface{}, eventID uuid.UUID) (int, error)
+	SumByRestaurantID(ctx context.Context, tx interface{}, restaurantID uuid.UUID) (int, error)
+	// SumByEventAndRestaurantID adds up the meals credited to one host of the event
+	SumByEventAndRestaurantID(ctx context.Context, tx interface{}, eventID, restaurantID uuid.UUID) (int, error)
+}
+
+type InventoryRepository interface {
+	Create(ctx context.Context, tx interface{}, item *domain.InventoryItem) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.InventoryItem, error)
+	GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.InventoryItem, error)
+	GetExpiring(ctx context.Context, restaurantID uuid.UUID, before time.Time) ([]*domain.InventoryItem, error)
+	Update(ctx context.Context, tx interface{}, item *domain.InventoryItem) error
+	DecrementQuantity(ctx context.Context, tx interface{}, id uuid.UUID, quantity float64) (bool, error)
+	Delete(ctx context.Context, tx interface{}, id uuid.UUID) error
+}
+
+type InventoryConsumptionRepository interface {
+	Create(ctx context.Context, tx interface{}, consumption *domain.InventoryConsumption) error
+	GetByEventID(ctx context.Context, eventID uuid.UUID) ([]*domain.InventoryConsumption, error)
+	GetByEventIDs(ctx context.Context, eventIDs []uuid.UUID) ([]*domain.InventoryConsumption, error)
+}
+
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, tx interface{}, attempt *domain.LoginAttempt) error
+	DeleteByUserID(ctx context.Context, tx interface{}, userID uuid.UUID) error
+}
+
 type TransactionManager interface {
 	BeginTx(ctx context.Context) (interface{}, error)
 	CommitTx(tx interface{}) error
 	RollbackTx(tx interface{}) error
 	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx interface{}) error) error
 }
+
+type RecoveryCodeRepository interface {
+	// ReplaceForUser deletes the user's recovery codes and stores the given ones
+	ReplaceForUser(ctx context.Context, tx interface{}, userID uuid.UUID, codes []*domain.RecoveryCode) error
+	// Use marks an unused code as used, it reports false when the user has no such code
+	Use(ctx context.Context, tx interface{}, userID uuid.UUID, codeHash string) (bool, error)
+	DeleteByUserID(ctx context.Context, tx interface{}, userID uuid.UUID) error
+}
+
+type SecurityPolicyRepository interface {
+	// Get returns the policy, or the default one when none was saved yet
+	Get(ctx context.Context) (*domain.SecurityPolicy, error)
+	Save(ctx context.Context, tx interface{}, policy *domain.SecurityPolicy) error
+}
+
+type ExternalIdentityRepository interface {
+	Create(ctx context.Context, tx interface{}, identity *domain.ExternalIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.ExternalIdentity, error)
+	DeleteByUserID(ctx context.Context, tx interface{}, userID uuid.UUID) error
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, tx interface{}, key *domain.APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	GetByRestaurantID(ctx context.Context, restaurantID uuid.UUID) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, tx interface{}, id uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, tx interface{}, entry *domain.AuditLog) error
+	List(ctx context.Context, filter domain.AuditLogFilter) ([]*domain.AuditLog, int, error)
+	// DeleteBefore removes the entries older than the given time and returns how many
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
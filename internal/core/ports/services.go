@@ -2,17 +2,75 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/SOU9OUR-DCF/dcf-backend.git/internal/core/domain"
 )
 
 type AuthService interface {
-	RegisterRestaurant(ctx context.Context, req domain.RestaurantRegisterRequest) (*domain.AuthResponse, domain.Token, error)
-	RegisterVolunteer(ctx context.Context, req domain.VolunteerRegisterRequest) (*domain.AuthResponse, domain.Token, error)
-	Login(ctx context.Context, req domain.LoginRequest) (*domain.AuthResponse, domain.Token, error)
-	ValidateToken(ctx context.Context, token string) (*domain.User, interface{}, error)
-	RefreshToken(ctx context.Context, token string) (*domain.AuthResponse, domain.Token, error)
-	Logout(ctx context.Context, token string) error
+	SessionIssuer
+	RegisterRestaurant(ctx context.Context, req domain.RestaurantRegisterRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error)
+	RegisterOrganization(ctx context.Context, req domain.OrganizationRegisterRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error)
+	RegisterVolunteer(ctx context.Context, req domain.VolunteerRegisterRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error)
+	RegisterStaff(ctx context.Context, req domain.StaffRegisterRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error)
+	Login(ctx context.Context, req domain.LoginRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, *domain.TwoFactorChallenge, error)
+	StartOIDCLogin(ctx context.Context, provider string) (*domain.OIDCAuthorization, error)
+	CompleteOIDCLogin(ctx context.Context, provider string, callback domain.OIDCCallback, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, *domain.TwoFactorChallenge, error)
+	CompleteTwoFactorLogin(ctx context.Context, req domain.TwoFactorLoginRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error)
+	ValidateToken(ctx context.Context, token string) (*domain.User, interface{}, *domain.Session, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*domain.AuthResponse, domain.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	ListSessions(ctx context.Context, userID string) ([]*domain.Session, error)
+	RevokeSession(ctx context.Context, userID string, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID string, currentSessionID string) error
+}
+
+// SessionIssuer signs in users whose identity was proven some other way than a password
+type SessionIssuer interface {
+	CompleteSignIn(ctx context.Context, user *domain.User, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, *domain.TwoFactorChallenge, error)
+}
+
+// LockoutService limits failed sign-ins per account and address and locks out accounts
+// that failed too often
+type LockoutService interface {
+	CheckLogin(ctx context.Context, email string, device domain.DeviceInfo) error
+	RecordFailure(ctx context.Context, user *domain.User, email string, device domain.DeviceInfo, reason string)
+	ResetFailures(ctx context.Context, email string) error
+	UnlockAccount(ctx context.Context, token string) error
+}
+
+type TwoFactorService interface {
+	CreateChallenge(ctx context.Context, user *domain.User) (*domain.TwoFactorChallenge, error)
+	VerifyChallenge(ctx context.Context, req domain.TwoFactorLoginRequest, device domain.DeviceInfo) (*domain.User, error)
+	SetupTwoFactor(ctx context.Context, userID string, req domain.TwoFactorSetupRequest) (*domain.TwoFactorSetup, error)
+	EnableTwoFactor(ctx context.Context, userID string, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID string, req domain.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID string, code string) ([]string, error)
+	TwoFactorEnrollmentRequired(ctx context.Context, userID string) (bool, error)
+}
+
+type MagicLinkService interface {
+	RequestMagicLink(ctx context.Context, email string, device domain.DeviceInfo) (string, time.Time, error)
+	ConsumeMagicLink(ctx context.Context, token string, binding string, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, *domain.TwoFactorChallenge, error)
+}
+
+type ImpersonationService interface {
+	StartImpersonation(ctx context.Context, adminID string, targetID string, req domain.ImpersonationRequest, device domain.DeviceInfo) (*domain.AuthResponse, domain.TokenPair, error)
+	EndImpersonation(ctx context.Context, userID string, sessionID string) error
+}
+
+// AccountService handles the emailed links and the credential changes of an account
+type AccountService interface {
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+	SendVerificationEmail(ctx context.Context, userID string) error
+	VerifyEmail(ctx context.Context, token string) error
+	SendBusinessVerificationEmail(ctx context.Context, restaurantID string) error
+	VerifyBusinessEmail(ctx context.Context, token string) error
+	ChangePassword(ctx context.Context, userID string, sessionID string, req domain.ChangePasswordRequest) error
+	RequestEmailChange(ctx context.Context, userID string, req domain.ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, token string) error
 }
 
 type UserService interface {
@@ -20,30 +78,48 @@ type UserService interface {
 	UpdateUser(ctx context.Context, user *domain.User) error
 	GetUserProfile(ctx context.Context, userID string, userType domain.UserType) (interface{}, error)
 	UpdateUserProfile(ctx context.Context, userID string, profile interface{}) error
+	ExportUserData(ctx context.Context, userID string) (*domain.UserDataExport, error)
+	RequestAccountDeletion(ctx context.Context, userID string, sessionID string, req domain.DeleteAccountRequest) (*domain.User, error)
+	CancelAccountDeletion(ctx context.Context, userID string) (*domain.User, error)
+	PurgeDueAccounts(ctx context.Context) (int, error)
 }
 
 type RestaurantService interface {
 	GetRestaurantByUserID(ctx context.Context, userID string) (*domain.Restaurant, error)
-	GetRestaurantStats(ctx context.Context, restaurantID string) (map[string]interface{}, error)
+	GetRestaurantStats(ctx context.Context, restaurantID string, branchID string) (map[string]interface{}, error)
 	UpdateRestaurant(ctx context.Context, restaurant *domain.Restaurant) error
+	CreateBranch(ctx context.Context, branch *domain.Branch) error
+	GetBranches(ctx context.Context, restaurantID string) ([]*domain.Branch, error)
+	GetBranch(ctx context.Context, id string) (*domain.Branch, error)
+	UpdateBranch(ctx context.Context, branch *domain.Branch) error
+	DeleteBranch(ctx context.Context, id string) error
 }
 
 type EventService interface {
 	CreateEvent(ctx context.Context, event *domain.Event) error
 	GetEventByID(ctx context.Context, id string) (*domain.Event, error)
-	GetUpcomingEvents(ctx context.Context, restaurantID string, limit, offset int) ([]*domain.Event, int, error)
-	GetTodayEvents(ctx context.Context, restaurantID string) ([]*domain.Event, error)
+	GetUpcomingEvents(ctx context.Context, restaurantID string, branchID string, limit, offset int) ([]*domain.Event, int, error)
+	GetTodayEvents(ctx context.Context, restaurantID string, branchID string) ([]*domain.Event, error)
 	UpdateEvent(ctx context.Context, event *domain.Event) error
 	UpdateEventStatus(ctx context.Context, id string, status domain.EventStatus) error
 	UpdateGuestCount(ctx context.Context, id string, count int) error
-	UpdateMealsServed(ctx context.Context, id string, count int) error
+	UpdateMealsServed(ctx context.Context, id string, hostID string, recordedBy string, count int) error
+	RecordMeals(ctx context.Context, eventID string, hostID string, recordedBy string, count int, note string) (*domain.MealLogEntry, error)
+	CorrectMealEntry(ctx context.Context, eventID string, entryID string, hostID string, recordedBy string, note string) (*domain.MealLogEntry, error)
+	GetMealLog(ctx context.Context, eventID string) ([]*domain.MealLogEntry, error)
 	DeleteEvent(ctx context.Context, id string) error
+	GetHosts(ctx context.Context, eventID string) ([]*domain.EventHost, error)
+	GetHost(ctx context.Context, id string) (*domain.EventHost, error)
+	AddHost(ctx context.Context, host *domain.EventHost) error
+	UpdateHost(ctx context.Context, host *domain.EventHost) error
+	RemoveHost(ctx context.Context, id string) error
 }
 
 type VolunteerService interface {
 	GetVolunteerByUserID(ctx context.Context, userID string) (*domain.Volunteer, error)
 	GetEventVolunteers(ctx context.Context, eventID string) ([]*domain.Volunteer, error)
 	GetPendingApplications(ctx context.Context, restaurantID string) ([]*domain.VolunteerApplication, error)
+	GetApplication(ctx context.Context, id string) (*domain.VolunteerApplication, error)
 	ApproveApplication(ctx context.Context, applicationID string) error
 	DeclineApplication(ctx context.Context, applicationID string) error
 	GetVolunteerCount(ctx context.Context, restaurantID string) (int, error)
@@ -54,3 +130,69 @@ type VolunteerService interface {
 	ApplyForEvent(ctx context.Context, volunteerID string, eventID string, role string) error
 	CheckInForEvent(ctx context.Context, volunteerID string, eventVolunteerID string) error
 }
+
+type InventoryService interface {
+	AddItem(ctx context.Context, item *domain.InventoryItem) error
+	GetItem(ctx context.Context, id string) (*domain.InventoryItem, error)
+	GetInventory(ctx context.Context, restaurantID string) ([]*domain.InventoryItem, error)
+	UpdateItem(ctx context.Context, item *domain.InventoryItem) error
+	DeleteItem(ctx context.Context, id string) error
+	GetExpiringItems(ctx context.Context, restaurantID string, within time.Duration) ([]*domain.InventoryItem, error)
+	RecordConsumption(ctx context.Context, eventID string, itemID string, quantity float64) (*domain.InventoryConsumption, error)
+	GetEventSummary(ctx context.Context, eventID string) (*domain.EventInventorySummary, error)
+}
+
+type AdminService interface {
+	CreateAdmin(ctx context.Context, email, username, password string) (*domain.User, error)
+	ListUsers(ctx context.Context, filter domain.ListFilter) ([]*domain.User, int, error)
+	GetUser(ctx context.Context, id string) (*domain.User, interface{}, error)
+	UpdateUser(ctx context.Context, id string, update domain.AdminUserUpdate) (*domain.User, error)
+	SuspendUser(ctx context.Context, id string, reason string) error
+	ReactivateUser(ctx context.Context, id string) error
+	ListRestaurants(ctx context.Context, filter domain.ListFilter) ([]*domain.Restaurant, int, error)
+	GetRestaurant(ctx context.Context, id string) (*domain.Restaurant, error)
+	UpdateRestaurant(ctx context.Context, id string, update domain.AdminRestaurantUpdate) (*domain.Restaurant, error)
+	ListEvents(ctx context.Context, filter domain.ListFilter) ([]*domain.Event, int, error)
+	UpdateEvent(ctx context.Context, id string, update domain.AdminEventUpdate) (*domain.Event, error)
+	ListApplications(ctx context.Context, filter domain.ListFilter) ([]*domain.VolunteerApplication, int, error)
+	UpdateApplicationStatus(ctx context.Context, id string, status string) error
+	GetSecurityPolicy(ctx context.Context) (*domain.SecurityPolicy, error)
+	UpdateSecurityPolicy(ctx context.Context, req domain.UpdateSecurityPolicyRequest) (*domain.SecurityPolicy, error)
+}
+
+type VerificationService interface {
+	UploadDocument(ctx context.Context, restaurantID string, documentType domain.DocumentType, fileName string, content io.Reader) (*domain.RestaurantDocument, error)
+	GetDocuments(ctx context.Context, restaurantID string) ([]*domain.RestaurantDocument, error)
+	GetDocument(ctx context.Context, id string) (*domain.RestaurantDocument, error)
+	OpenDocument(ctx context.Context, document *domain.RestaurantDocument) (io.ReadCloser, error)
+	DeleteDocument(ctx context.Context, id string) error
+	ApproveRestaurant(ctx context.Context, restaurantID string) error
+	RejectRestaurant(ctx context.Context, restaurantID string, reason string) error
+}
+
+type MembershipService interface {
+	ResolveMembership(ctx context.Context, userID string, restaurantID string) (*domain.RestaurantMember, error)
+	GetMemberships(ctx context.Context, userID string) ([]*domain.RestaurantMember, error)
+	GetMembers(ctx context.Context, restaurantID string) ([]*domain.RestaurantMember, error)
+	GetMember(ctx context.Context, id string) (*domain.RestaurantMember, error)
+	UpdateMember(ctx context.Context, id string, update domain.UpdateMemberRequest) error
+	RemoveMember(ctx context.Context, id string) error
+	InviteMember(ctx context.Context, restaurantID string, invitedBy string, req domain.InviteMemberRequest) (*domain.RestaurantInvitation, error)
+	GetInvitations(ctx context.Context, restaurantID string) ([]*domain.RestaurantInvitation, error)
+	GetInvitation(ctx context.Context, id string) (*domain.RestaurantInvitation, error)
+	RevokeInvitation(ctx context.Context, id string) error
+	AcceptInvitation(ctx context.Context, userID string, token string) (*domain.RestaurantMember, error)
+}
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, restaurantID string, userID string, req domain.CreateAPIKeyRequest) (*domain.CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context, restaurantID string) ([]*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, restaurantID string, id string) error
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*domain.APIKey, *domain.User, *domain.RestaurantMember, error)
+}
+
+type AuditService interface {
+	Record(ctx context.Context, entry *domain.AuditLog) error
+	ListAuditLogs(ctx context.Context, filter domain.AuditLogFilter) ([]*domain.AuditLog, int, error)
+	PurgeExpired(ctx context.Context) (int, error)
+}
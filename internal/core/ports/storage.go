@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"io"
+)
+
+// FileStorage stores uploaded files under opaque keys chosen by the caller
+type FileStorage interface {
+	Save(ctx context.Context, key string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
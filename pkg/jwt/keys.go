@@ -0,0 +1,304 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Key is one key of a KeySet. Keys without a private part only verify tokens, they are
+// kept around so tokens signed before a rotation stay valid until they expire.
+type Key struct {
+	ID        string
+	CreatedAt time.Time
+	path      string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    crypto.PublicKey
+}
+
+// keyTimeFormat is the layout of the creation time in key file names
+const keyTimeFormat = "20060102T150405Z"
+
+// minMissReloadInterval limits how often tokens with an unknown kid make the set read the
+// directory again
+const minMissReloadInterval = 10 * time.Second
+
+// KeySet holds the keys in a directory, one PEM file per key named
+// "<created>_<kid>.pem", with the creation time in keyTimeFormat. Files named "<kid>.pem"
+// count as the oldest keys. Ed25519 and RSA keys are supported. The newest private key
+// signs, every key verifies.
+type KeySet struct {
+	dir          string
+	mu           sync.RWMutex
+	keys         []*Key // newest first
+	missReloadAt time.Time
+}
+
+// LoadKeySet reads the keys in dir, generating a first key when there is none
+func LoadKeySet(dir string) (*KeySet, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create key directory: %w", err)
+	}
+
+	ks := &KeySet{dir: dir}
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+
+	if ks.SigningKey() == nil {
+		if err := ks.generate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+// Reload reads the key directory again, picking up keys added or removed by hand
+func (ks *KeySet) Reload() error {
+	paths, err := filepath.Glob(filepath.Join(ks.dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		key, err := readKey(path)
+		if err != nil {
+			return fmt.Errorf("failed to read key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	// Replicas sharing the directory must agree on the signing key, even for keys created
+	// in the same second
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		}
+		return keys[i].ID > keys[j].ID
+	})
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// Rotate generates a new signing key once the current one is older than every and deletes
+// the keys it generated that stopped signing more than retention ago. Keys added by hand,
+// such as public keys kept to verify the tokens of another issuer, are never deleted. A
+// zero every disables rotation.
+func (ks *KeySet) Rotate(every, retention time.Duration) error {
+	if err := ks.Reload(); err != nil {
+		return err
+	}
+
+	if every <= 0 {
+		return nil
+	}
+
+	if current := ks.SigningKey(); current == nil || time.Since(current.CreatedAt) >= every {
+		if err := ks.generate(); err != nil {
+			return err
+		}
+	}
+
+	ks.mu.RLock()
+	keys := ks.keys
+	ks.mu.RUnlock()
+
+	// A key stopped signing when the next newer signing key was created
+	var retiredAt time.Time
+	for _, key := range keys {
+		if !retiredAt.IsZero() && time.Since(retiredAt) > retention && key.generated() {
+			if err := os.Remove(key.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		if key.private != nil {
+			retiredAt = key.CreatedAt
+		}
+	}
+
+	return ks.Reload()
+}
+
+// generated reports whether the key is a private key named like the ones generate writes
+func (k *Key) generated() bool {
+	if k.private == nil {
+		return false
+	}
+
+	created, id, ok := strings.Cut(strings.TrimSuffix(filepath.Base(k.path), ".pem"), "_")
+	if !ok || len(id) != 16 {
+		return false
+	}
+	if _, err := hex.DecodeString(id); err != nil {
+		return false
+	}
+	_, err := time.Parse(keyTimeFormat, created)
+	return err == nil
+}
+
+// SigningKey returns the newest key with a private part
+func (ks *KeySet) SigningKey() *Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, key := range ks.keys {
+		if key.private != nil {
+			return key
+		}
+	}
+	return nil
+}
+
+// VerificationKey returns the key with the given ID. An unknown ID may be a key another
+// replica just generated, so the directory is read again unless an unknown ID made it
+// happen moments ago.
+func (ks *KeySet) VerificationKey(id string) *Key {
+	if key := ks.lookup(id); key != nil {
+		return key
+	}
+
+	ks.mu.Lock()
+	throttled := time.Since(ks.missReloadAt) < minMissReloadInterval
+	if !throttled {
+		ks.missReloadAt = time.Now()
+	}
+	ks.mu.Unlock()
+
+	if throttled || ks.Reload() != nil {
+		return nil
+	}
+	return ks.lookup(id)
+}
+
+func (ks *KeySet) lookup(id string) *Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, key := range ks.keys {
+		if key.ID == id {
+			return key
+		}
+	}
+	return nil
+}
+
+// Keys returns every key of the set, newest first
+func (ks *KeySet) Keys() []*Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return append([]*Key(nil), ks.keys...)
+}
+
+func (ks *KeySet) generate() error {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return err
+	}
+
+	// A random ID cannot collide with the key another replica generates at the same time
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return err
+	}
+	name := time.Now().UTC().Format(keyTimeFormat) + "_" + hex.EncodeToString(random) + ".pem"
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(ks.dir, name), data, 0600); err != nil {
+		return fmt.Errorf("failed to write key: %w", err)
+	}
+
+	return ks.Reload()
+}
+
+func readKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	id, createdAt := parseKeyName(filepath.Base(path))
+	key := &Key{
+		ID:        id,
+		CreatedAt: createdAt,
+		path:      path,
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, k, k.Public()
+	case ed25519.PublicKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, k
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, k
+	default:
+		return nil, errors.New("only Ed25519 and RSA keys are supported")
+	}
+
+	return key, nil
+}
+
+// parseKeyName returns the key ID and creation time of a key file name. The creation time
+// is zero when the name has none.
+func parseKeyName(name string) (string, time.Time) {
+	base := strings.TrimSuffix(name, ".pem")
+	if created, id, ok := strings.Cut(base, "_"); ok {
+		if t, err := time.Parse(keyTimeFormat, created); err == nil && id != "" {
+			return id, t
+		}
+	}
+
+	// Keys generated before the name held the creation time were named after it
+	if t, err := time.Parse(keyTimeFormat, base); err == nil {
+		return base, t
+	}
+	return base, time.Time{}
+}
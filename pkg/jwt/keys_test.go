@@ -0,0 +1,306 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/jwt"
+	jwtlib "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var generatedKeyName = regexp.MustCompile(`^\d{8}T\d{6}Z_[0-9a-f]{16}\.pem$`)
+
+// writeKey stores a new Ed25519 private key under the given file name
+func writeKey(t *testing.T, dir, name string) {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0600))
+}
+
+// writePublicKey stores the public part of a new Ed25519 key under the given file name
+func writePublicKey(t *testing.T, dir, name string) {
+	t.Helper()
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0600))
+}
+
+func keyFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	require.NoError(t, err)
+
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = filepath.Base(path)
+	}
+	return names
+}
+
+func keyTime(d time.Duration) string {
+	return time.Now().Add(-d).UTC().Format("20060102T150405Z")
+}
+
+func TestLoadKeySetGeneratesFirstKey(t *testing.T) {
+	dir := t.TempDir()
+
+	ks, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+
+	files := keyFiles(t, dir)
+	require.Len(t, files, 1)
+	assert.Regexp(t, generatedKeyName, files[0])
+
+	key := ks.SigningKey()
+	require.NotNil(t, key)
+	assert.Regexp(t, `^[0-9a-f]{16}$`, key.ID)
+	assert.WithinDuration(t, time.Now(), key.CreatedAt, 2*time.Second)
+}
+
+func TestKeyNames(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		file        string
+		wantID      string
+		wantCreated time.Time
+	}{
+		{"creation time and kid", "20240301T123000Z_0123456789abcdef.pem", "0123456789abcdef", created},
+		{"creation time only", "20240301T123000Z.pem", "20240301T123000Z", created},
+		{"kid only", "my-key.pem", "my-key", time.Time{}},
+		{"underscore without creation time", "my_key.pem", "my_key", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeKey(t, dir, tt.file)
+
+			ks, err := jwt.LoadKeySet(dir)
+			require.NoError(t, err)
+
+			key := ks.VerificationKey(tt.wantID)
+			require.NotNil(t, key)
+			assert.True(t, tt.wantCreated.Equal(key.CreatedAt), "created at %s", key.CreatedAt)
+		})
+	}
+}
+
+func TestSigningKeyIgnoresModificationTime(t *testing.T) {
+	dir := t.TempDir()
+	newer := keyTime(time.Hour) + "_bbbbbbbbbbbbbbbb.pem"
+	writeKey(t, dir, keyTime(2*time.Hour)+"_aaaaaaaaaaaaaaaa.pem")
+	writeKey(t, dir, newer)
+
+	// A restore from backup touches the older file last
+	past := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, newer), past, past))
+
+	ks, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "bbbbbbbbbbbbbbbb", ks.SigningKey().ID)
+}
+
+func TestSigningKeyTieBreak(t *testing.T) {
+	// Replicas that rotate in the same second still agree on the signing key
+	created := keyTime(0)
+	dir := t.TempDir()
+	writeKey(t, dir, created+"_1111111111111111.pem")
+	writeKey(t, dir, created+"_ffffffffffffffff.pem")
+
+	ks, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "ffffffffffffffff", ks.SigningKey().ID)
+}
+
+func TestRotate(t *testing.T) {
+	tests := []struct {
+		name        string
+		keyAge      time.Duration
+		every       time.Duration
+		wantRotated bool
+	}{
+		{"current key is recent", time.Hour, 24 * time.Hour, false},
+		{"current key is due", 25 * time.Hour, 24 * time.Hour, true},
+		{"rotation disabled", 25 * time.Hour, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeKey(t, dir, keyTime(tt.keyAge)+"_0000000000000001.pem")
+
+			ks, err := jwt.LoadKeySet(dir)
+			require.NoError(t, err)
+			require.NoError(t, ks.Rotate(tt.every, time.Hour))
+
+			if tt.wantRotated {
+				assert.NotEqual(t, "0000000000000001", ks.SigningKey().ID)
+				assert.Len(t, ks.Keys(), 2)
+			} else {
+				assert.Equal(t, "0000000000000001", ks.SigningKey().ID)
+				assert.Len(t, ks.Keys(), 1)
+			}
+		})
+	}
+}
+
+func TestRotateDeletesRetiredKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, keyTime(72*time.Hour)+"_0000000000000001.pem") // retired 48 hours ago
+	writeKey(t, dir, keyTime(48*time.Hour)+"_0000000000000002.pem") // retired 30 minutes ago
+	writeKey(t, dir, keyTime(30*time.Minute)+"_0000000000000003.pem")
+
+	ks, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+	require.NoError(t, ks.Rotate(24*time.Hour, time.Hour))
+
+	assert.Nil(t, ks.VerificationKey("0000000000000001"))
+	assert.NotNil(t, ks.VerificationKey("0000000000000002"))
+	assert.Equal(t, "0000000000000003", ks.SigningKey().ID)
+	assert.Len(t, keyFiles(t, dir), 2)
+}
+
+func TestRotateKeepsKeysAddedByHand(t *testing.T) {
+	dir := t.TempDir()
+	writePublicKey(t, dir, keyTime(96*time.Hour)+"_0000000000000001.pem")
+	writePublicKey(t, dir, "partner.pem")
+	writeKey(t, dir, keyTime(72*time.Hour)+"_legacy.pem")
+	writeKey(t, dir, keyTime(48*time.Hour)+"_0000000000000002.pem") // retired 30 minutes ago
+	writeKey(t, dir, keyTime(30*time.Minute)+"_0000000000000003.pem")
+
+	ks, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+	require.NoError(t, ks.Rotate(24*time.Hour, time.Hour))
+
+	// Every key is older than the retention, yet only keys Rotate generated may go
+	assert.NotNil(t, ks.VerificationKey("0000000000000001"))
+	assert.NotNil(t, ks.VerificationKey("partner"))
+	assert.NotNil(t, ks.VerificationKey("legacy"))
+	assert.Len(t, keyFiles(t, dir), 5)
+}
+
+func TestValidateToken(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+	service := jwt.NewService(ks, time.Minute)
+
+	token, exp, err := service.GenerateToken("user-1", "session-1", "volunteer")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), exp, 2*time.Second)
+
+	parsed, _, err := new(jwtlib.Parser).ParseUnverified(token, &jwt.Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, ks.SigningKey().ID, parsed.Header["kid"])
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "session-1", claims.ID)
+	assert.Equal(t, "volunteer", claims.Role)
+
+	// Tokens signed before a rotation stay valid
+	require.NoError(t, ks.Rotate(time.Nanosecond, time.Hour))
+	_, err = service.ValidateToken(token)
+	assert.NoError(t, err)
+}
+
+func TestValidateTokenRejects(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+	service := jwt.NewService(ks, time.Minute)
+
+	expired, _, err := jwt.NewService(ks, -time.Minute).GenerateToken("user-1", "session-1", "volunteer")
+	require.NoError(t, err)
+
+	claims := &jwt.Claims{RegisteredClaims: jwtlib.RegisteredClaims{
+		Subject:   "user-1",
+		ExpiresAt: jwtlib.NewNumericDate(time.Now().Add(time.Minute)),
+	}}
+
+	unknownKid := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, claims)
+	unknownKid.Header["kid"] = "unknown"
+	unknownKidToken, err := unknownKid.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	// An HMAC token keyed with the public key must not pass for an Ed25519 one
+	wrongAlg := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, claims)
+	wrongAlg.Header["kid"] = ks.SigningKey().ID
+	wrongAlgToken, err := wrongAlg.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", expired},
+		{"unknown kid", unknownKidToken},
+		{"algorithm not of the key", wrongAlgToken},
+		{"malformed", "not.a.token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ValidateToken(tt.token)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidateTokenOfAnotherReplica(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, keyTime(48*time.Hour)+"_0000000000000001.pem")
+
+	replicaA, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+	replicaB, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+
+	// A rotates, B has not read the directory since
+	require.NoError(t, replicaA.Rotate(24*time.Hour, time.Hour))
+	token, _, err := jwt.NewService(replicaA, time.Minute).GenerateToken("user-1", "session-1", "volunteer")
+	require.NoError(t, err)
+
+	_, err = jwt.NewService(replicaB, time.Minute).ValidateToken(token)
+	assert.NoError(t, err)
+}
+
+func TestJWKS(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := jwt.LoadKeySet(dir)
+	require.NoError(t, err)
+
+	set := jwt.NewService(ks, time.Minute).JWKS()
+	require.Len(t, set.Keys, 1)
+
+	key := set.Keys[0]
+	assert.Equal(t, ks.SigningKey().ID, key.Kid)
+	assert.Equal(t, "OKP", key.Kty)
+	assert.Equal(t, "Ed25519", key.Crv)
+	assert.Equal(t, "EdDSA", key.Alg)
+	assert.NotEmpty(t, key.X)
+}
@@ -13,30 +13,40 @@ type Claims struct {
 }
 
 type Service struct {
-	secretKey []byte
+	keys      *KeySet
 	expiresIn time.Duration
 }
 
-func NewService(secretKey string, expiresIn time.Duration) *Service {
+func NewService(keys *KeySet, expiresIn time.Duration) *Service {
 	return &Service{
-		secretKey: []byte(secretKey),
+		keys:      keys,
 		expiresIn: expiresIn,
 	}
 }
 
-func (s *Service) GenerateToken(userID string) (string, time.Time, error) {
+// GenerateToken signs a token for the session of a user, the session ID is the token ID.
+// The kid header names the key that signed it.
+func (s *Service) GenerateToken(userID string, sessionID string, role string) (string, time.Time, error) {
+	key := s.keys.SigningKey()
+	if key == nil {
+		return "", time.Time{}, errors.New("no signing key available")
+	}
+
 	expirationTime := time.Now().Add(s.expiresIn)
 
 	claims := &Claims{
+		Role: role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
 			Subject:   userID,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.secretKey)
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.ID
+	tokenString, err := token.SignedString(key.private)
 
 	return tokenString, expirationTime, err
 }
@@ -45,10 +55,16 @@ func (s *Service) ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key := s.keys.VerificationKey(kid)
+		if key == nil {
+			return nil, errors.New("unknown signing key")
+		}
+		// The algorithm comes from the key, never from the token
+		if token.Method.Alg() != key.method.Alg() {
 			return nil, errors.New("unexpected signing method")
 		}
-		return s.secretKey, nil
+		return key.public, nil
 	})
 
 	if err != nil {
@@ -0,0 +1,85 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	period = 30 * time.Second
+	digits = 6
+	// Codes from one period before and after are accepted too, to allow for clock drift
+	skew = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 secret, as authenticator apps expect it
+func GenerateSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// URI returns the otpauth:// URI authenticator apps enroll from, usually shown as a QR code
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(int(period.Seconds())))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Code returns the code for the secret at the given time (RFC 6238)
+func Code(secret string, t time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid secret: %w", err)
+	}
+	return code(key, uint64(t.Unix()/int64(period.Seconds()))), nil
+}
+
+// Validate reports whether the code matches the secret at the given time
+func Validate(passcode, secret string, t time.Time) bool {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(key) == 0 || len(passcode) != digits {
+		return false
+	}
+
+	counter := t.Unix() / int64(period.Seconds())
+	for i := int64(-skew); i <= skew; i++ {
+		expected := code(key, uint64(counter+i))
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(passcode)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// code computes the HOTP value of the counter (RFC 4226)
+func code(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", digits, value%1000000)
+}
@@ -0,0 +1,111 @@
+package totp_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SOU9OUR-DCF/dcf-backend.git/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 seed of RFC 6238 appendix B, "12345678901234567890", in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// The RFC lists 8-digit codes, the 6-digit codes are their last six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := totp.Code(rfcSecret, time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "time %d", tt.unix)
+	}
+}
+
+func TestCodeLowercaseSecret(t *testing.T) {
+	got, err := totp.Code(strings.ToLower(rfcSecret), time.Unix(59, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", got)
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	_, err := totp.Code("not base32!", time.Now())
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	// 1111111111 is in period 37037037, which runs from 1111111110 to 1111111139
+	now := time.Unix(1111111111, 0)
+	codeAt := func(offset time.Duration) string {
+		code, err := totp.Code(rfcSecret, now.Add(offset))
+		require.NoError(t, err)
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		passcode string
+		secret   string
+		want     bool
+	}{
+		{"current period", codeAt(0), rfcSecret, true},
+		{"previous period", codeAt(-30 * time.Second), rfcSecret, true},
+		{"next period", codeAt(30 * time.Second), rfcSecret, true},
+		{"two periods ago", codeAt(-60 * time.Second), rfcSecret, false},
+		{"two periods ahead", codeAt(60 * time.Second), rfcSecret, false},
+		{"wrong code", "000000", rfcSecret, false},
+		{"too short", codeAt(0)[:5], rfcSecret, false},
+		{"too long", codeAt(0) + "0", rfcSecret, false},
+		{"empty secret", codeAt(0), "", false},
+		{"invalid secret", codeAt(0), "not base32!", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, totp.Validate(tt.passcode, tt.secret, now))
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	other, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+	assert.True(t, totp.Validate(code, secret, time.Now()))
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(totp.URI("DCF", "jane@example.com", rfcSecret))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/DCF:jane@example.com", uri.Path)
+
+	query := uri.Query()
+	assert.Equal(t, rfcSecret, query.Get("secret"))
+	assert.Equal(t, "DCF", query.Get("issuer"))
+	assert.Equal(t, "SHA1", query.Get("algorithm"))
+	assert.Equal(t, "6", query.Get("digits"))
+	assert.Equal(t, "30", query.Get("period"))
+}